@@ -23,10 +23,24 @@ type (
 	Cond = queryset.Cond
 	AND  = queryset.AND
 	OR   = queryset.OR
+	Expr = queryset.Expr
+	Q    = queryset.Q
+
+	Aggregation = queryset.Aggregation
 )
 
 var (
 	ToOR = queryset.ToOR
+	F    = queryset.F
+	And  = queryset.And
+	Or   = queryset.Or
+	Not  = queryset.Not
+
+	Count = queryset.Count
+	Sum   = queryset.Sum
+	Avg   = queryset.Avg
+	Max   = queryset.Max
+	Min   = queryset.Min
 )
 
 // EachOR re-exports the generic EachOR function from queryset package
@@ -2,11 +2,19 @@ package norm
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
+	"iter"
+	"maps"
 	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/leisurelicht/norm/internal/operator"
 	"github.com/leisurelicht/norm/internal/queryset"
 )
 
@@ -16,8 +24,34 @@ const (
 	InsertTemp = "INSERT INTO %s (%s) VALUES (%s)"
 	UpdateTemp = "UPDATE %s SET %s"
 	DeleteTemp = "DELETE FROM %s"
+	JoinTemp   = " %s JOIN %s ON %s"
 )
 
+const (
+	innerJoin = "INNER"
+	leftJoin  = "LEFT"
+)
+
+const (
+	// softDeleteOption is the tag option of the soft delete column, e.g. `db:"deleted_at,softDelete"`.
+	softDeleteOption = "softDelete"
+	// autoCreateTimeOption is the tag option of the column set to the current time on create, e.g. `db:"create_time,autoCreateTime"`.
+	autoCreateTimeOption = "autoCreateTime"
+	// autoUpdateTimeOption is the tag option of the column set to the current time on create and update.
+	autoUpdateTimeOption = "autoUpdateTime"
+	// versionOption is the tag option of the optimistic locking column, e.g. `db:"version,version"`.
+	versionOption = "version"
+	// pkOption is the tag option of the primary key column, e.g. `db:"uid,pk"`.
+	pkOption = "pk"
+	// defaultPK is the primary key column when no column is tagged with pk.
+	defaultPK = "id"
+	// legacySoftDeleteColumn is the soft delete column when no column is tagged with softDelete.
+	legacySoftDeleteColumn = "is_deleted"
+)
+
+// defaultBatchSize is the number of rows BulkUpdate sends in one statement unless BatchSize is called.
+const defaultBatchSize = 500
+
 const (
 	ModelTypeNotStructError      = "model must be a pointer to struct"
 	ModelTypeNotSliceError       = "model must be a pointer to slice"
@@ -34,7 +68,35 @@ const (
 	UpdateColumnNotExistError    = "update column [%s] not exist"
 	ColumnNotExistError          = "column [%s] not exist"
 	MustBeCalledError            = "[%s] must be called after [%s]"
+	MustBeCalledBeforeError      = "[%s] must be called before [%s]"
+	JoinTableTypeError           = "join table should be a Controller or a table name, not [%T]"
+	JoinTableDuplicateError      = "join table [%s] is already in the query"
+	JoinOnEmptyError             = "join on condition is empty"
+	JoinOnTypeError              = "join on [%s] should be a column name, not [%T]"
 	UnsupportedControllerError   = "[%s] not supported for %s"
+	AggregationEmptyError        = "aggregations are empty"
+	AggregationAsteriskError     = "[*] is only supported by Count, not [%s]"
+	AggregationAliasError        = "aggregation alias [%s] is duplicated"
+	AnnotateColumnsError         = "[Annotate] needs the columns of GroupBy or Select as a string slice"
+	ControllerTypeError          = "[%s] needs a controller created by NewController, not [%T]"
+	CursorOrderByError           = "[%s] needs OrderBy with a string slice"
+	CursorCalledError            = "[After] or [Before] can only be called once"
+	CursorInvalidError           = "cursor is invalid: %w"
+	CursorMismatchError          = "cursor is for OrderBy [%s], not [%s]"
+	CursorRowTypeError           = "cursor row should be a map or a struct, not [%T]"
+	CursorColumnError            = "cursor column [%s] is missing or NULL in the row"
+	ChunkSizeError               = "[%s] size must be positive"
+	OperatorNotSupportedError    = "[%s] is not supported by the database of the operator"
+	LockCalledError              = "[ForUpdate] or [ForShare] can only be called once"
+	UpsertColumnsError           = "upsert has no columns to update"
+	SubqueryColumnsError         = "subquery of the in lookup must select one column, not %d"
+	BulkUpdateKeyError           = "bulk update row [%d] has no key column [%s]"
+	BatchSizeError               = "batch size must be positive"
+	LockOptionCalledError        = "[SkipLocked] or [NoWait] can only be called once"
+	SoftDeleteColumnError        = "[%s] needs a soft delete column, add is_deleted or tag one with softDelete"
+	DeletedScopeCalledError      = "[WithDeleted] or [OnlyDeleted] can only be called once"
+	PrimaryKeyError              = "[%s] needs a primary key, tag one with pk or add the id column"
+	VersionNotSupportedError     = "[%s] can not check the version, the database of the operator does not report affected rows"
 )
 
 type controllerCall struct {
@@ -43,14 +105,37 @@ type controllerCall struct {
 }
 
 var (
-	ctlFilter  = controllerCall{Name: "Filter", Flag: queryset.QsFilter}
-	ctlExclude = controllerCall{Name: "Exclude", Flag: queryset.QsExclude}
-	ctlWhere   = controllerCall{Name: "Where", Flag: queryset.QsWhere}
-	ctlSelect  = controllerCall{Name: "Select", Flag: queryset.QsSelect}
-	ctlLimit   = controllerCall{Name: "Limit", Flag: queryset.QsLimit}
-	ctlOrderBy = controllerCall{Name: "OrderBy", Flag: queryset.QsOrderBy}
-	ctlGroupBy = controllerCall{Name: "GroupBy", Flag: queryset.QsGroupBy}
-	ctlHaving  = controllerCall{Name: "Having", Flag: queryset.QsHaving}
+	ctlFilter    = controllerCall{Name: "Filter", Flag: queryset.QsFilter}
+	ctlExclude   = controllerCall{Name: "Exclude", Flag: queryset.QsExclude}
+	ctlWhere     = controllerCall{Name: "Where", Flag: queryset.QsWhere}
+	ctlSelect    = controllerCall{Name: "Select", Flag: queryset.QsSelect}
+	ctlLimit     = controllerCall{Name: "Limit", Flag: queryset.QsLimit}
+	ctlOrderBy   = controllerCall{Name: "OrderBy", Flag: queryset.QsOrderBy}
+	ctlGroupBy   = controllerCall{Name: "GroupBy", Flag: queryset.QsGroupBy}
+	ctlHaving    = controllerCall{Name: "Having", Flag: queryset.QsHaving}
+	ctlJoin      = controllerCall{Name: "Join", Flag: queryset.QsJoin}
+	ctlAnnotate  = controllerCall{Name: "Annotate", Flag: queryset.QsAnnotate}
+	ctlDistinct  = controllerCall{Name: "Distinct", Flag: queryset.QsDistinct}
+	ctlAfter     = controllerCall{Name: "After", Flag: queryset.QsAfter}
+	ctlBefore    = controllerCall{Name: "Before", Flag: queryset.QsBefore}
+	ctlForUpdate = controllerCall{Name: "ForUpdate", Flag: queryset.QsForUpdate}
+	ctlForShare  = controllerCall{Name: "ForShare", Flag: queryset.QsForShare}
+
+	ctlWithDeleted = controllerCall{Name: "WithDeleted", Flag: queryset.QsWithDeleted}
+	ctlOnlyDeleted = controllerCall{Name: "OnlyDeleted", Flag: queryset.QsOnlyDeleted}
+)
+
+// ErrStaleObject is returned by Update when the data has the version of the records and none of them
+// has it anymore, because another update increased it since the records were read.
+var ErrStaleObject = errors.New("stale object")
+
+// errStopIteration is returned to the operator by the FindEach callback when the loop body breaks.
+var errStopIteration = errors.New("stop iteration")
+
+var (
+	anyType         = reflect.TypeFor[any]()
+	nullInt64Type   = reflect.TypeFor[sql.NullInt64]()
+	nullFloat64Type = reflect.TypeFor[sql.NullFloat64]()
 )
 
 var _ Controller = (*Impl)(nil)
@@ -62,20 +147,52 @@ type (
 		Filter(filter ...any) Controller
 		Exclude(exclude ...any) Controller
 		Where(cond string, args ...any) Controller
+		OrWhere(cond string, args ...any) Controller
 		Select(columns any) Controller
+		Distinct(columns ...string) Controller
 		Limit(pageSize, pageNum int64) Controller
 		OrderBy(orderBy any) Controller
+		After(cursor string) Controller
+		Before(cursor string) Controller
+		Cursor(row any) (cursor string, err error)
 		GroupBy(groupBy any) Controller
 		Having(having string, args ...any) Controller
+		Annotate(aggregations ...Aggregation) Controller
+		ForUpdate() Controller
+		ForShare() Controller
+		SkipLocked() Controller
+		NoWait() Controller
+		WithDeleted() Controller
+		OnlyDeleted() Controller
+		Get(pk any) (result map[string]any, err error)
+		Join(table any, on Cond) Controller
+		LeftJoin(table any, on Cond) Controller
+		InnerJoin(table any, on Cond) Controller
 		Create(data any) (idOrNum int64, err error)
+		CreateWith(data any, options ...CreateOption) (num int64, err error)
+		Upsert(data any, updateColumns []string, conflictColumns ...string) (num int64, err error)
 		Remove() (num int64, err error)
 		Update(data map[string]any) (num int64, err error)
+		UpdateByPK(pk any, data map[string]any) (num int64, err error)
+		Save(modelPtr any) (num int64, err error)
+		BatchSize(size int) Controller
+		BulkUpdate(keyColumn string, rows []map[string]any) (num int64, err error)
 		Count() (num int64, err error)
+		CountDistinct(column string) (num int64, err error)
+		Aggregate(aggregations ...Aggregation) (result map[string]any, err error)
 		FindOne() (result map[string]any, err error)
 		FindOneModel(modelPtr any) (err error)
 		FindAll() (result []map[string]any, err error)
 		FindAllModel(modelSlicePtr any) (err error)
+		Pluck(column string) (values []any, err error)
+		Rows() iter.Seq2[map[string]any, error]
+		Chunk(size int, fn func(rows []map[string]any) error) (err error)
+		ChunkModel(size int, modelSlicePtr any, fn func() error) (err error)
+		QuerySQL() (query string, args []any, err error)
+		ColumnSQL() (query string, args []any, err error)
 		Delete() (num int64, err error)
+		DeleteByPK(pk any) (num int64, err error)
+		Restore() (num int64, err error)
 		Exist() (exist bool, error error)
 		List() (num int64, data []map[string]any, err error)
 		GetOrCreate(data map[string]any) (result map[string]any, err error)
@@ -89,11 +206,40 @@ type (
 		modelSlicePtr  any
 		fieldNameSlice []string
 		fieldNameMap   map[string]struct{}
+		fieldTypes     map[string]reflect.Type
 		fieldRows      string
+		tableName      string
+		pk             string
+		softDelete     softDelete
+		createTimes    []string
+		updateTimes    []string
+		version        string
+		now            func() time.Time
+		joins          map[string]map[string]struct{}
+		selectColumns  []string
+		groupColumns   []string
+		orderColumns   []string
+		annotations    []Aggregation
+		lock           string
+		lockOption     string
+		batchSize      int
 		operator       Operator
 		qs             queryset.QuerySet
 		called         queryset.CallFlag
 	}
+
+	// ControllerOption changes the controllers created by NewController.
+	ControllerOption func(options *controllerOptions)
+
+	controllerOptions struct {
+		now func() time.Time
+	}
+
+	// softDelete is the column Delete marks the records with, see newSoftDelete.
+	softDelete struct {
+		column    string
+		timestamp bool
+	}
 )
 
 // NewController creates a new Controller instance with the provided connection, operator, and model.
@@ -101,7 +247,7 @@ type (
 // The model can be a struct or a slice of structs, and the operator must implement the Operator interface.
 // The connection is used to execute queries, and the operator provides methods for database operations.
 // It returns a function that takes a context and returns a Controller instance.
-func NewController(op Operator, m any) func(ctx context.Context) Controller {
+func NewController(op Operator, m any, options ...ControllerOption) func(ctx context.Context) Controller {
 	// createModelPointerAndSlice call must be at the beginning of this function,
 	// for it will check type of the m(model) is a struct
 	mPtr, mSlicePtr := createModelPointerAndSlice(m)
@@ -110,21 +256,53 @@ func NewController(op Operator, m any) func(ctx context.Context) Controller {
 
 	filedNameMap := strSlice2Map(fieldNameSlice)
 
-	fieldRows := strings.Join(rawFieldNames(m, op.GetDBTag(), false), ",")
+	fieldTypes := rawFieldTypes(m, op.GetDBTag())
+
+	fieldRows := strings.Join(quoteFieldNames(fieldNameSlice, op.GetQuote()), ",")
+
+	softDelete := newSoftDelete(m, op.GetDBTag(), fieldTypes)
+
+	createTimes := taggedColumns(m, op.GetDBTag(), autoCreateTimeOption)
+	updateTimes := taggedColumns(m, op.GetDBTag(), autoUpdateTimeOption)
+
+	version := taggedColumn(m, op.GetDBTag(), versionOption)
+
+	pk := taggedColumn(m, op.GetDBTag(), pkOption)
+	if _, ok := filedNameMap[defaultPK]; pk == "" && ok {
+		pk = defaultPK
+	}
+
+	opts := controllerOptions{now: time.Now}
+	for _, option := range options {
+		option(&opts)
+	}
 
-	op = op.SetTableName(getTableName(m))
+	op = op.SetTableName(operator.QuoteIdent(op.GetQuote(), getTableName(m))).SetPrimaryKey(pk)
 
 	return func(ctx context.Context) Controller {
 		if ctx == nil {
 			ctx = context.Background()
 		}
+		// the ctx of a hook carries the session of WithSession, see BeforeCreateHook
+		op := op
+		if session := ctx.Value(sessionKey{}); session != nil {
+			op = op.WithSession(session)
+		}
 		return &Impl{
 			context:        ctx,
 			modelPtr:       mPtr,
 			modelSlicePtr:  mSlicePtr,
 			fieldNameSlice: fieldNameSlice,
 			fieldNameMap:   filedNameMap,
+			fieldTypes:     fieldTypes,
 			fieldRows:      fieldRows,
+			tableName:      strings.Trim(op.GetTableName(), "`\""),
+			pk:             pk,
+			softDelete:     softDelete,
+			createTimes:    createTimes,
+			updateTimes:    updateTimes,
+			version:        version,
+			now:            opts.now,
 			operator:       op,
 			qs:             queryset.NewQuerySet(op),
 			called:         0,
@@ -132,10 +310,38 @@ func NewController(op Operator, m any) func(ctx context.Context) Controller {
 	}
 }
 
+// WithClock sets the clock of the auto timestamps and of Delete, it is time.Now by default,
+// e.g. WithClock(func() time.Time { return time.Now().UTC() }).
+func WithClock(now func() time.Time) ControllerOption {
+	return func(options *controllerOptions) {
+		options.now = now
+	}
+}
+
+// newSoftDelete returns the soft delete column of the model, it is the column tagged with softDelete,
+// e.g. `db:"deleted_at,softDelete"`, or the is_deleted column, and its deleted records are excluded
+// from the queries. Delete sets a time column to the current time and any other column to true.
+func newSoftDelete(m any, tag string, fieldTypes map[string]reflect.Type) softDelete {
+	column := taggedColumn(m, tag, softDeleteOption)
+	if column == "" {
+		column = legacySoftDeleteColumn
+	}
+	typ, ok := fieldTypes[column]
+	if !ok {
+		return softDelete{}
+	}
+	return softDelete{column: column, timestamp: isTimeType(typ)}
+}
+
 func (m *Impl) ctx() context.Context {
 	return m.context
 }
 
+// quote wraps a column name with the identifier quote of the operator's dialect.
+func (m *Impl) quote(column string) string {
+	return operator.QuoteIdent(m.operator.GetQuote(), column)
+}
+
 func (m *Impl) setCalled(f controllerCall) {
 	m.called = m.called | f.Flag
 }
@@ -154,10 +360,33 @@ func (m *Impl) checkCalled(f ...controllerCall) ([]string, bool) {
 	return calledMethod, len(calledMethod) != 0
 }
 
+// validateColumn checks a column against the model, a qualified column like source.name
+// is checked against the model of the joined table, or accepted if the table was joined by name.
+func (m *Impl) validateColumn(column string) bool {
+	table, name, qualified := strings.Cut(column, ".")
+	if !qualified {
+		_, ok := m.fieldNameMap[column]
+		return ok
+	}
+
+	fieldNameMap := m.fieldNameMap
+	if table != m.tableName {
+		var joined bool
+		if fieldNameMap, joined = m.joins[table]; !joined {
+			return false
+		}
+		if fieldNameMap == nil {
+			return true
+		}
+	}
+	_, ok := fieldNameMap[name]
+	return ok
+}
+
 func (m *Impl) validateColumns(columns []string) (validatedColumns []string, err error) {
 	var unknownColumns []string
 	for _, v := range columns {
-		if _, ok := m.fieldNameMap[v]; ok {
+		if m.validateColumn(v) {
 			validatedColumns = append(validatedColumns, v)
 		} else {
 			unknownColumns = append(unknownColumns, v)
@@ -171,6 +400,153 @@ func (m *Impl) validateColumns(columns []string) (validatedColumns []string, err
 	return validatedColumns, nil
 }
 
+// validateLookups checks the qualified fields of the filter lookups, like "source.name__contains".
+func (m *Impl) validateLookups(filter []any) bool {
+	for _, f := range filter {
+		var lookups map[string]any
+		switch v := f.(type) {
+		case Cond:
+			lookups = v
+		case AND:
+			lookups = v
+		case OR:
+			lookups = v
+		case queryset.Q:
+			if !m.validateLookups(v.Conditions()) {
+				return false
+			}
+		}
+		for key, value := range lookups {
+			if field := queryset.LookupField(key); strings.Contains(field, ".") && !m.validateColumn(field) {
+				m.setError(ColumnNotExistError, field)
+				return false
+			}
+			if column, ok := m.validateExpr(value); !ok {
+				m.setError(ColumnNotExistError, column)
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// validateExpr checks the columns of the value if it is an expression, and returns the first unknown one.
+func (m *Impl) validateExpr(value any) (string, bool) {
+	expr, ok := value.(Expr)
+	if !ok {
+		return "", true
+	}
+	for _, column := range expr.Columns() {
+		if !m.validateColumn(column) {
+			return column, false
+		}
+	}
+	return "", true
+}
+
+// validateAggregations checks the columns and the aliases of the aggregations.
+func (m *Impl) validateAggregations(aggregations []Aggregation) bool {
+	if len(aggregations) == 0 {
+		m.setError(AggregationEmptyError)
+		return false
+	}
+
+	aliases := make(map[string]struct{}, len(aggregations))
+	for _, a := range aggregations {
+		if a.Column() == Asterisk {
+			if a.Function() != queryset.AggCount {
+				m.setError(AggregationAsteriskError, a.Function())
+				return false
+			}
+		} else if !m.validateColumn(a.Column()) {
+			m.setError(ColumnNotExistError, a.Column())
+			return false
+		}
+		if _, ok := aliases[a.Alias()]; ok {
+			m.setError(AggregationAliasError, a.Alias())
+			return false
+		}
+		aliases[a.Alias()] = struct{}{}
+	}
+	return true
+}
+
+// columnType returns the type a column is scanned into, it is a pointer to the model field type
+// so that NULL can be scanned, or any for the columns of joined tables.
+func (m *Impl) columnType(column string) reflect.Type {
+	if table, name, qualified := strings.Cut(column, "."); qualified {
+		if table != m.tableName {
+			return anyType
+		}
+		column = name
+	}
+
+	typ, ok := m.fieldTypes[column]
+	if !ok {
+		return anyType
+	}
+	if typ.Kind() != reflect.Pointer {
+		typ = reflect.PointerTo(typ)
+	}
+	return typ
+}
+
+// aggregationType returns the type an aggregation is scanned into. Count is an integer, Avg is a float,
+// Sum is an integer for integer columns or a float, Max and Min have the type of the column.
+func (m *Impl) aggregationType(a Aggregation) reflect.Type {
+	switch a.Function() {
+	case queryset.AggCount:
+		return nullInt64Type
+	case queryset.AggAvg:
+		return nullFloat64Type
+	case queryset.AggSum:
+		typ := m.columnType(a.Column())
+		if typ.Kind() == reflect.Pointer {
+			switch typ.Elem().Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+				reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				return nullInt64Type
+			}
+		}
+		return nullFloat64Type
+	default:
+		return m.columnType(a.Column())
+	}
+}
+
+// aggregationQuery returns the query selecting the columns and the aggregations, and the columns of its result.
+func (m *Impl) aggregationQuery(selectColumns []string, aggregations []Aggregation) (query string, args []any, columns []resultColumn) {
+	rows := make([]string, 0, len(selectColumns)+len(aggregations))
+	columns = make([]resultColumn, 0, len(selectColumns)+len(aggregations))
+
+	for _, column := range selectColumns {
+		rows = append(rows, m.quote(column))
+		columns = append(columns, resultColumn{name: unqualifiedName(column), typ: m.columnType(column)})
+	}
+	for _, a := range aggregations {
+		rows = append(rows, a.SQL(m.quote))
+		columns = append(columns, resultColumn{name: a.Alias(), typ: m.aggregationType(a)})
+	}
+
+	query, args = m.buildQuery(strings.Join(rows, ","))
+	return query, args, columns
+}
+
+// annotatedQuery returns the query of the GroupBy rows with the annotations.
+// The rows have the columns of Select, or of GroupBy if Select was not called.
+func (m *Impl) annotatedQuery() (query string, args []any, columns []resultColumn, err error) {
+	selectColumns := m.selectColumns
+	if !m.hasCalled(ctlSelect) {
+		selectColumns = m.groupColumns
+	}
+	if !m.hasCalled(ctlGroupBy) || len(selectColumns) == 0 {
+		return "", nil, nil, errors.New(AnnotateColumnsError)
+	}
+
+	query, args, columns = m.aggregationQuery(selectColumns, m.annotations)
+	return query, args, columns, nil
+}
+
 func (m *Impl) setError(format string, a ...any) {
 	m.qs.SetError(format, a...)
 }
@@ -210,14 +586,59 @@ func (m *Impl) preCheckData(opName string, data map[string]any, unsupportedMetho
 	return nil
 }
 
+// softDeleteCondition returns the condition matching the records of table which are not deleted, or the deleted ones,
+// table is m itself or a controller joined to it.
+func (m *Impl) softDeleteCondition(table *Impl, deleted bool) (condition string, args []any) {
+	column := table.softDelete.column
+	if m.hasCalled(ctlJoin) {
+		column = table.tableName + "." + column
+	}
+	column = m.quote(column)
+
+	if !table.softDelete.timestamp {
+		return column + "=?", []any{deleted}
+	}
+	if deleted {
+		return column + " IS NOT NULL", nil
+	}
+	return column + " IS NULL", nil
+}
+
+// whereSQL returns the WHERE clause of the query set, which excludes the soft deleted records
+// unless WithDeleted is called, or only keeps them if OnlyDeleted is called.
+func (m *Impl) whereSQL() (filterSQL string, args []any) {
+	filterSQL, args = m.qs.GetQuerySet()
+
+	deleted := m.hasCalled(ctlOnlyDeleted)
+	if m.softDelete.column == "" || m.hasCalled(ctlWithDeleted) {
+		return filterSQL, args
+	}
+
+	condition, conditionArgs := m.softDeleteCondition(m, deleted)
+	return andWhere(filterSQL, condition), slices.Concat(args, conditionArgs)
+}
+
+// joinWhereSQL returns the join clauses followed by the WHERE clause, with the args of both in order.
+func (m *Impl) joinWhereSQL() (sql string, args []any) {
+	joinSQL, joinArgs := m.qs.GetJoinSQL()
+	filterSQL, filterArgs := m.whereSQL()
+	return joinSQL + filterSQL, slices.Concat(joinArgs, filterArgs)
+}
+
 func (m *Impl) buildQuery(selectRows string) (query string, args []any) {
 	if selectRows == "" || selectRows == Asterisk {
 		selectRows = m.fieldRows
+		if m.hasCalled(ctlJoin) {
+			selectRows = m.qualifiedFieldRows()
+		}
+	}
+	if m.hasCalled(ctlDistinct) {
+		selectRows = "DISTINCT " + selectRows
 	}
 
 	query = fmt.Sprintf(SelectTemp, selectRows, m.operator.GetTableName())
 
-	filterSQL, filterArgs := m.qs.GetQuerySet()
+	filterSQL, filterArgs := m.joinWhereSQL()
 	query += filterSQL
 	args = append(args, filterArgs...)
 
@@ -232,8 +653,25 @@ func (m *Impl) buildQuery(selectRows string) (query string, args []any) {
 	return query, args
 }
 
+// qualifiedFieldRows returns the model columns prefixed with the table, used when tables are joined.
+func (m *Impl) qualifiedFieldRows() string {
+	fieldNames := make([]string, len(m.fieldNameSlice))
+	for i, name := range m.fieldNameSlice {
+		fieldNames[i] = m.tableName + "." + name
+	}
+	return strings.Join(quoteFieldNames(fieldNames, m.operator.GetQuote()), ",")
+}
+
 func (m *Impl) reset() {
 	m.qs.Reset()
+	m.joins = nil
+	m.selectColumns = nil
+	m.groupColumns = nil
+	m.orderColumns = nil
+	m.annotations = nil
+	m.lock = ""
+	m.lockOption = ""
+	m.batchSize = 0
 	m.called = 0
 }
 
@@ -247,24 +685,33 @@ func (m *Impl) Reset() Controller {
 
 func (m *Impl) WithSession(session any) Controller {
 	m.operator = m.operator.WithSession(session)
+	m.context = context.WithValue(m.context, sessionKey{}, session)
 	return m
 }
 
 // Filter adds a filter condition containing objects that match the given lookup parameters
-// It only accepts norm.Cond / norm.AND / norm.OR.
+// It only accepts norm.Cond / norm.AND / norm.OR / norm.Q.
 func (m *Impl) Filter(filter ...any) Controller {
 	m.setCalled(ctlFilter)
 
+	if !m.validateLookups(filter) {
+		return m
+	}
+
 	m.qs.FilterToSQL(queryset.NotNot, filter...)
 
 	return m
 }
 
 // Exclude adds an exclusion condition containing objects that do not match the given lookup parameters.
-// It only accepts norm.Cond / norm.AND/norm.OR.
+// It only accepts norm.Cond / norm.AND / norm.OR / norm.Q.
 func (m *Impl) Exclude(exclude ...any) Controller {
 	m.setCalled(ctlExclude)
 
+	if !m.validateLookups(exclude) {
+		return m
+	}
+
 	m.qs.FilterToSQL(queryset.IsNot, exclude...)
 
 	return m
@@ -273,6 +720,7 @@ func (m *Impl) Exclude(exclude ...any) Controller {
 // Where adds a WHERE clause to the query.
 // It accepts a condition string and optional arguments.
 // The condition string should be a valid SQL WHERE clause, and the arguments will be used to replace placeholders in the condition.
+// It can be mixed with Filter and Exclude, the conditions are joined by AND in the order they are called.
 func (m *Impl) Where(cond string, args ...any) Controller {
 	m.setCalled(ctlWhere)
 
@@ -281,6 +729,16 @@ func (m *Impl) Where(cond string, args ...any) Controller {
 	return m
 }
 
+// OrWhere is like Where, but the condition is joined with the previous conditions by OR.
+// A condition added after it by AND applies to the whole OR, e.g. Where(a).OrWhere(b).Filter(c) is (a OR b) AND c.
+func (m *Impl) OrWhere(cond string, args ...any) Controller {
+	m.setCalled(ctlWhere)
+
+	m.qs.OrWhereToSQL(cond, args...)
+
+	return m
+}
+
 // Select adds a SELECT clause to the query.
 // It accepts a string or a slice of strings for selecting columns.
 // If you pass a string, it should be a comma-separated list of columns and will not be validated.
@@ -309,6 +767,7 @@ func (m *Impl) Select(selects any) Controller {
 			return m
 		}
 
+		m.selectColumns = validatedColumns
 		m.qs.SliceSelectToSQL(validatedColumns)
 	default:
 		m.setError(SelectColumnsTypeError)
@@ -317,6 +776,18 @@ func (m *Impl) Select(selects any) Controller {
 	return m
 }
 
+// Distinct removes the duplicate rows found by FindOne / FindAll / FindAllModel.
+// The columns are optional, they are validated and selected as Select([]string{...}) does.
+func (m *Impl) Distinct(columns ...string) Controller {
+	m.setCalled(ctlDistinct)
+
+	if len(columns) > 0 {
+		m.Select(columns)
+	}
+
+	return m
+}
+
 // Limit adds a LIMIT clause to the query.
 // It accepts pageSize and pageNum to control the number of records returned.
 // It requires OrderBy to be called first. If OrderBy has not been called, it will return an error.
@@ -361,7 +832,7 @@ func (m *Impl) OrderBy(orderBy any) Controller {
 			if strings.HasPrefix(by, "-") {
 				needValidate = by[1:]
 			}
-			if _, ok := m.fieldNameMap[needValidate]; ok {
+			if m.validateColumn(needValidate) {
 				validatedOrderBy = append(validatedOrderBy, by)
 			} else {
 				unknownColumns = append(unknownColumns, by)
@@ -374,6 +845,7 @@ func (m *Impl) OrderBy(orderBy any) Controller {
 			return m
 		}
 
+		m.orderColumns = validatedOrderBy
 		m.qs.OrderByToSQL(validatedOrderBy)
 	default:
 		m.setError(OrderByColumnsTypeError)
@@ -382,6 +854,86 @@ func (m *Impl) OrderBy(orderBy any) Controller {
 	return m
 }
 
+func (m *Impl) seek(opName, cursor string, before bool) Controller {
+	if m.hasCalled(ctlAfter) || m.hasCalled(ctlBefore) {
+		m.setError(CursorCalledError)
+		return m
+	}
+	if before {
+		m.setCalled(ctlBefore)
+	} else {
+		m.setCalled(ctlAfter)
+	}
+
+	if !m.hasCalled(ctlOrderBy) {
+		m.setError(MustBeCalledError, opName, "OrderBy")
+		return m
+	}
+	if len(m.orderColumns) == 0 {
+		m.setError(CursorOrderByError, opName)
+		return m
+	}
+
+	types := make([]reflect.Type, len(m.orderColumns))
+	for i, by := range m.orderColumns {
+		types[i] = m.columnType(strings.TrimPrefix(by, "-"))
+	}
+	values, err := decodeCursor(cursor, m.orderColumns, types)
+	if err != nil {
+		m.setError("%w", err)
+		return m
+	}
+
+	m.qs.SeekToSQL(m.orderColumns, values, before)
+	return m
+}
+
+// After keeps the records after the cursor in the order of OrderBy, it is the keyset pagination
+// which does not slow down on deep pages like the OFFSET of Limit, e.g.
+//
+//	ctl(ctx).OrderBy([]string{"-create_time", "id"}).After(cursor).Limit(20, 1).FindAll()
+//
+// The cursor is returned by Cursor for the last record of the previous page, the OrderBy must be
+// a string slice of not NULL columns and the last one should be unique, like the primary key.
+func (m *Impl) After(cursor string) Controller {
+	return m.seek("After", cursor, false)
+}
+
+// Before keeps the records before the cursor in the order of OrderBy, the cursor is returned by Cursor
+// for the first record of the next page. The records are still returned in the order of OrderBy.
+func (m *Impl) Before(cursor string) Controller {
+	return m.seek("Before", cursor, true)
+}
+
+// Cursor returns the cursor of After and Before for a row found with the current OrderBy,
+// the row is a map of FindAll or a model struct, or a pointer to it.
+func (m *Impl) Cursor(row any) (cursor string, err error) {
+	if err = m.haveError(); err != nil {
+		return "", err
+	}
+	if len(m.orderColumns) == 0 {
+		return "", fmt.Errorf(CursorOrderByError, "Cursor")
+	}
+
+	data, ok := row.(map[string]any)
+	if !ok {
+		if reflect.Indirect(reflect.ValueOf(row)).Kind() != reflect.Struct {
+			return "", fmt.Errorf(CursorRowTypeError, row)
+		}
+		data = modelStruct2Map(row, m.operator.GetDBTag())
+	}
+
+	values := make([]any, len(m.orderColumns))
+	for i, by := range m.orderColumns {
+		column := unqualifiedName(strings.TrimPrefix(by, "-"))
+		if values[i] = data[column]; values[i] == nil {
+			return "", fmt.Errorf(CursorColumnError, column)
+		}
+	}
+
+	return encodeCursor(m.orderColumns, values)
+}
+
 // GroupBy adds a GROUP BY clause to the query.
 // It accepts a string or a slice of strings for grouping columns.
 // If you pass a string, it should be a comma-separated list of columns and will not be validated.
@@ -406,6 +958,7 @@ func (m *Impl) GroupBy(groupBy any) Controller {
 			return m
 		}
 
+		m.groupColumns = validatedColumns
 		m.qs.SliceGroupByToSQL(validatedColumns)
 	default:
 		m.setError(GroupByColumnsTypeError)
@@ -422,10 +975,253 @@ func (m *Impl) Having(having string, args ...any) Controller {
 	return m
 }
 
+// Annotate adds aggregations computed for each GroupBy row, e.g. GroupBy([]string{"type"}).Annotate(Count("*")).
+// The rows found by FindOne / FindAll have the columns of Select or GroupBy and the aliases of the aggregations.
+func (m *Impl) Annotate(aggregations ...Aggregation) Controller {
+	m.setCalled(ctlAnnotate)
+
+	annotations := append(append([]Aggregation{}, m.annotations...), aggregations...)
+	if !m.validateAggregations(annotations) {
+		return m
+	}
+	m.annotations = annotations
+
+	return m
+}
+
+// lockSQL returns the row locking clause of ForUpdate / ForShare and their option.
+func (m *Impl) lockSQL() string {
+	return m.lock + m.lockOption
+}
+
+func (m *Impl) setLock(called controllerCall, lock string) Controller {
+	if m.hasCalled(ctlForUpdate) || m.hasCalled(ctlForShare) {
+		m.setError(LockCalledError)
+		return m
+	}
+	m.setCalled(called)
+
+	if m.lock = m.operator.LockSQL(lock); m.lock == "" {
+		m.setError(OperatorNotSupportedError, called.Name)
+	}
+	return m
+}
+
+func (m *Impl) setLockOption(opName, option string) Controller {
+	if !m.hasCalled(ctlForUpdate) && !m.hasCalled(ctlForShare) {
+		m.setError(MustBeCalledError, opName, "ForUpdate or ForShare")
+		return m
+	}
+	if m.lockOption != "" {
+		m.setError(LockOptionCalledError)
+		return m
+	}
+
+	if m.lockOption = m.operator.LockSQL(option); m.lockOption == "" {
+		m.setError(OperatorNotSupportedError, opName)
+	}
+	return m
+}
+
+// ForUpdate locks the rows found by the find methods with SELECT ... FOR UPDATE until the end of the transaction,
+// so it should be used with WithSession. The other methods ignore it, ClickHouse and SQLite do not support it.
+func (m *Impl) ForUpdate() Controller {
+	return m.setLock(ctlForUpdate, operator.LockForUpdate)
+}
+
+// ForShare locks the rows found by the find methods with SELECT ... FOR SHARE, like ForUpdate.
+func (m *Impl) ForShare() Controller {
+	return m.setLock(ctlForShare, operator.LockForShare)
+}
+
+// SkipLocked skips the rows locked by other transactions instead of waiting for them,
+// e.g. ForUpdate().SkipLocked() for a job queue. It must be called after ForUpdate or ForShare.
+func (m *Impl) SkipLocked() Controller {
+	return m.setLockOption("SkipLocked", operator.LockSkipLocked)
+}
+
+// NoWait fails at once if a row is locked by another transaction instead of waiting for it.
+// It must be called after ForUpdate or ForShare.
+func (m *Impl) NoWait() Controller {
+	return m.setLockOption("NoWait", operator.LockNoWait)
+}
+
+func (m *Impl) setDeletedScope(called controllerCall) Controller {
+	if m.hasCalled(ctlWithDeleted) || m.hasCalled(ctlOnlyDeleted) {
+		m.setError(DeletedScopeCalledError)
+		return m
+	}
+	m.setCalled(called)
+
+	if called == ctlOnlyDeleted && m.softDelete.column == "" {
+		m.setError(SoftDeleteColumnError, called.Name)
+	}
+	return m
+}
+
+// WithDeleted keeps the soft deleted records in the query, which are excluded by default
+// if the model has a soft delete column.
+func (m *Impl) WithDeleted() Controller {
+	return m.setDeletedScope(ctlWithDeleted)
+}
+
+// OnlyDeleted only keeps the soft deleted records in the query.
+func (m *Impl) OnlyDeleted() Controller {
+	return m.setDeletedScope(ctlOnlyDeleted)
+}
+
+func (m *Impl) join(joinType string, table any, on Cond) Controller {
+	if methods, called := m.checkCalled(ctlFilter, ctlExclude, ctlWhere, ctlSelect, ctlOrderBy, ctlGroupBy, ctlHaving); called {
+		m.setError(MustBeCalledBeforeError, "Join", strings.Join(methods, ", "))
+		return m
+	}
+	m.setCalled(ctlJoin)
+
+	var (
+		joinTable    string
+		fieldNameMap map[string]struct{}
+		scope        *Impl
+	)
+	switch t := table.(type) {
+	case *Impl:
+		joinTable, fieldNameMap = t.tableName, t.fieldNameMap
+		if t.softDelete.column != "" && !t.hasCalled(ctlWithDeleted) {
+			scope = t
+		}
+	case string:
+		joinTable = strings.Trim(t, "`\"")
+	default:
+		m.setError(JoinTableTypeError, table)
+		return m
+	}
+	if _, ok := m.joins[joinTable]; ok || joinTable == m.tableName {
+		m.setError(JoinTableDuplicateError, joinTable)
+		return m
+	}
+	if len(on) == 0 {
+		m.setError(JoinOnEmptyError)
+		return m
+	}
+	if m.joins == nil {
+		m.joins = make(map[string]map[string]struct{})
+	}
+	m.joins[joinTable] = fieldNameMap
+
+	// the left columns belong to the tables already in the query, the right ones to the joined table
+	left := make([]string, 0, len(on))
+	for k := range on {
+		left = append(left, k)
+	}
+	sort.Strings(left)
+
+	conditions := make([]string, 0, len(on))
+	for _, l := range left {
+		r, ok := on[l].(string)
+		if !ok {
+			m.setError(JoinOnTypeError, l, on[l])
+			return m
+		}
+		if !strings.Contains(l, ".") {
+			l = m.tableName + "." + l
+		}
+		if !strings.Contains(r, ".") {
+			r = joinTable + "." + r
+		}
+		for _, column := range []string{l, r} {
+			if !m.validateColumn(column) {
+				m.setError(ColumnNotExistError, column)
+				return m
+			}
+		}
+		conditions = append(conditions, m.quote(l)+" = "+m.quote(r))
+	}
+
+	// the deleted records of the joined model are excluded in ON, so a LEFT JOIN still keeps the rows of the main table
+	var args []any
+	if scope != nil {
+		condition, conditionArgs := m.softDeleteCondition(scope, scope.hasCalled(ctlOnlyDeleted))
+		conditions, args = append(conditions, condition), conditionArgs
+	}
+
+	m.qs.JoinToSQL(m.tableName, fmt.Sprintf(JoinTemp, joinType, m.quote(joinTable), strings.Join(conditions, " AND ")), args...)
+
+	return m
+}
+
+// Join adds an INNER JOIN clause to the query, it is the same as InnerJoin.
+// The table can be a Controller, whose model is used to validate the columns, or a table name.
+// The on condition maps the columns of the tables already in the query to the columns of the joined table,
+// e.g. Cond{"source_id": "id"} gives ON `property`.`source_id` = `source`.`id`.
+// Columns of the joined table can then be used as "source.name" in Filter, Exclude, Select, OrderBy and GroupBy.
+// If the Controller has a soft delete column, its deleted records are excluded in the ON condition,
+// call WithDeleted or OnlyDeleted on it before Join to change that. A table joined by name is not scoped.
+// It must be called before the other query methods.
+func (m *Impl) Join(table any, on Cond) Controller {
+	return m.join(innerJoin, table, on)
+}
+
+// LeftJoin adds a LEFT JOIN clause to the query, see Join.
+func (m *Impl) LeftJoin(table any, on Cond) Controller {
+	return m.join(leftJoin, table, on)
+}
+
+// InnerJoin adds an INNER JOIN clause to the query, see Join.
+func (m *Impl) InnerJoin(table any, on Cond) Controller {
+	return m.join(innerJoin, table, on)
+}
+
+// timestamp returns the time for the auto timestamp column, in Unix seconds if the column is an integer.
+func (m *Impl) timestamp(column string, now time.Time) any {
+	switch m.fieldTypes[column].Kind() {
+	case reflect.Int, reflect.Int32, reflect.Int64, reflect.Uint, reflect.Uint32, reflect.Uint64:
+		return now.Unix()
+	default:
+		return now
+	}
+}
+
+// createTimestamps returns copies of the rows with the autoCreateTime and autoUpdateTime columns
+// set to the current time, unless the rows have a non zero value for them.
+func (m *Impl) createTimestamps(rows []map[string]any) []map[string]any {
+	if len(m.createTimes) == 0 && len(m.updateTimes) == 0 {
+		return rows
+	}
+
+	now := m.now()
+	stamped := make([]map[string]any, len(rows))
+	for i, row := range rows {
+		stamped[i] = maps.Clone(row)
+		for _, column := range slices.Concat(m.createTimes, m.updateTimes) {
+			if v, ok := row[column]; !ok || v == nil || reflect.ValueOf(v).IsZero() {
+				stamped[i][column] = m.timestamp(column, now)
+			}
+		}
+	}
+	return stamped
+}
+
+// updateTimestamps returns a copy of the data with the autoUpdateTime columns set to the current time,
+// unless the data has them.
+func (m *Impl) updateTimestamps(data map[string]any) map[string]any {
+	if len(m.updateTimes) == 0 {
+		return data
+	}
+
+	now := m.now()
+	data = maps.Clone(data)
+	for _, column := range m.updateTimes {
+		if _, ok := data[column]; !ok {
+			data[column] = m.timestamp(column, now)
+		}
+	}
+	return data
+}
+
 func (m *Impl) create(data map[string]any) (id int64, err error) {
 	if len(data) == 0 {
 		return 0, errors.New("create " + DataEmptyError)
 	}
+	data = m.createTimestamps([]map[string]any{data})[0]
 
 	var (
 		rows []string
@@ -436,7 +1232,7 @@ func (m *Impl) create(data map[string]any) (id int64, err error) {
 		if _, ok := data[k]; !ok {
 			continue
 		}
-		rows = append(rows, fmt.Sprintf("`%s`", k))
+		rows = append(rows, m.quote(k))
 		args = append(args, data[k])
 	}
 
@@ -445,10 +1241,11 @@ func (m *Impl) create(data map[string]any) (id int64, err error) {
 	return m.operator.Insert(m.ctx(), sql, args...)
 }
 
-func (m *Impl) bulkCreate(data []map[string]any) (num int64, err error) {
-	if len(data) == 0 {
+func (m *Impl) bulkCreate(insertTemp string, data []map[string]any) (num int64, err error) {
+	if len(data) == 0 || len(data[0]) == 0 {
 		return 0, errors.New("bulk create " + DataEmptyError)
 	}
+	data = m.createTimestamps(data)
 
 	var (
 		rows []string
@@ -459,94 +1256,574 @@ func (m *Impl) bulkCreate(data []map[string]any) (num int64, err error) {
 		if _, ok := data[0][k]; !ok {
 			continue
 		}
-		rows = append(rows, fmt.Sprintf("`%s`", k))
+		rows = append(rows, m.quote(k))
 		args = append(args, k)
 	}
+	if len(rows) == 0 {
+		return 0, errors.New("bulk create " + DataEmptyError)
+	}
 
-	sql := fmt.Sprintf(InsertTemp, m.operator.GetTableName(), strings.Join(rows, ","), strings.Repeat("?,", len(rows)-1)+"?")
+	sql := fmt.Sprintf(insertTemp, m.operator.GetTableName(), strings.Join(rows, ","), strings.Repeat("?,", len(rows)-1)+"?")
 
 	return m.operator.BulkInsert(m.ctx(), sql, args, data)
 }
 
+// createModel creates the record of the model struct, a zero primary key is left to the database
+// and the generated id is written back to the model if it is a pointer.
+func (m *Impl) createModel(model any) (id int64, err error) {
+	data := modelStruct2Map(model, m.operator.GetDBTag())
+	if pk, ok := data[m.pk]; ok && isZeroValue(pk) {
+		delete(data, m.pk)
+	}
+
+	if id, err = m.create(data); err != nil {
+		return 0, err
+	}
+
+	m.setPK(model, id)
+	return id, nil
+}
+
+// setPK writes the generated id to the zero primary key of the model if it is a pointer.
+func (m *Impl) setPK(model any, id int64) {
+	rv := reflect.ValueOf(model)
+	if id == 0 || m.pk == "" || rv.Kind() != reflect.Pointer {
+		return
+	}
+	if field := fieldByColumn(rv.Elem(), m.operator.GetDBTag(), m.pk); field.IsValid() && field.IsZero() {
+		setField(field, id)
+	}
+}
+
 // Create creates a new record in the database with the provided data map.
+// A model struct with a zero primary key gets the generated id, which is written back to a struct pointer.
 // It returns the ID of the created record or the number of records inserted, and any error encountered.
 func (m *Impl) Create(data any) (idOrNum int64, err error) {
-	if err = m.preCheck("Create", ctlFilter, ctlExclude, ctlWhere, ctlSelect, ctlOrderBy, ctlGroupBy, ctlHaving); err != nil {
+	if err = m.preCheck("Create", ctlFilter, ctlExclude, ctlWhere, ctlSelect, ctlOrderBy, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
+		return 0, err
+	}
+
+	data, models, err := m.beforeCreate(data)
+	if err != nil {
 		return 0, err
 	}
 
 	switch d := data.(type) {
 	case map[string]any:
-		return m.create(d)
+		if idOrNum, err = m.create(d); err == nil && len(models) == 1 {
+			m.setPK(models[0], idOrNum)
+		}
 	case []map[string]any:
-		return m.bulkCreate(d)
+		idOrNum, err = m.bulkCreate(InsertTemp, d)
 	default:
 		v := reflect.ValueOf(data)
 		if v.Kind() == reflect.Pointer {
 			v = v.Elem()
 		}
-		if v.Kind() == reflect.Struct {
-			return m.create(modelStruct2Map(data, m.operator.GetDBTag()))
-		} else if v.Kind() == reflect.Slice {
-			return m.bulkCreate(modelStructSlice2MapSlice(data, m.operator.GetDBTag()))
+		switch v.Kind() {
+		case reflect.Struct:
+			idOrNum, err = m.createModel(data)
+		case reflect.Slice:
+			idOrNum, err = m.bulkCreate(InsertTemp, modelStructSlice2MapSlice(data, m.operator.GetDBTag()))
+		default:
+			return 0, fmt.Errorf(CreateDataTypeError, reflect.TypeOf(data).Kind())
 		}
 	}
-	return 0, fmt.Errorf(CreateDataTypeError, reflect.TypeOf(data).Kind())
+	if err != nil {
+		return idOrNum, err
+	}
+
+	return idOrNum, callHook(m, AfterCreateHook.AfterCreate, models...)
+}
+
+// CreateOption changes how CreateWith inserts the rows.
+type CreateOption func(options *createOptions)
+
+type createOptions struct {
+	name string
+	mode string
+}
+
+// IgnoreDuplicates skips the rows whose unique key already exists instead of failing,
+// INSERT IGNORE on MySQL, INSERT OR IGNORE on SQLite and ON CONFLICT DO NOTHING on PostgreSQL.
+func IgnoreDuplicates() CreateOption {
+	return func(options *createOptions) {
+		options.name, options.mode = "IgnoreDuplicates", operator.InsertIgnore
+	}
+}
+
+// ReplaceDuplicates replaces the rows whose unique key already exists,
+// REPLACE INTO on MySQL and INSERT OR REPLACE on SQLite.
+func ReplaceDuplicates() CreateOption {
+	return func(options *createOptions) {
+		options.name, options.mode = "ReplaceDuplicates", operator.InsertReplace
+	}
+}
+
+// CreateWith creates the records like Create with the options, e.g. CreateWith(rows, norm.IgnoreDuplicates()).
+// The data is a map, a slice of maps, a model struct or a slice of them, the last option wins.
+// It returns the affected rows of the database, so the ignored rows are not counted,
+// MySQL counts a replaced row as 2. An error is returned if the database does not support the option.
+func (m *Impl) CreateWith(data any, options ...CreateOption) (num int64, err error) {
+	if err = m.preCheck("CreateWith", ctlFilter, ctlExclude, ctlWhere, ctlSelect, ctlOrderBy, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
+		return 0, err
+	}
+
+	var opts createOptions
+	for _, option := range options {
+		option(&opts)
+	}
+
+	insertTemp := InsertTemp
+	if opts.mode != "" {
+		if insertTemp = m.operator.InsertSQL(opts.mode); insertTemp == "" {
+			return 0, fmt.Errorf(OperatorNotSupportedError, opts.name)
+		}
+	}
+
+	data, models, err := m.beforeCreate(data)
+	if err != nil {
+		return 0, err
+	}
+
+	rows, err := m.createRows(data)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return 0, errors.New("create " + DataEmptyError)
+	}
+
+	if num, err = m.bulkCreate(insertTemp, rows); err != nil {
+		return num, err
+	}
+
+	return num, callHook(m, AfterCreateHook.AfterCreate, models...)
+}
+
+// createRows converts the data of Create to rows, it is a map, a slice of maps, a model struct or a slice of them.
+func (m *Impl) createRows(data any) ([]map[string]any, error) {
+	switch d := data.(type) {
+	case map[string]any:
+		return []map[string]any{d}, nil
+	case []map[string]any:
+		return d, nil
+	}
+
+	v := reflect.ValueOf(data)
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		return []map[string]any{modelStruct2Map(data, m.operator.GetDBTag())}, nil
+	case reflect.Slice:
+		return modelStructSlice2MapSlice(data, m.operator.GetDBTag()), nil
+	default:
+		return nil, fmt.Errorf(CreateDataTypeError, v.Kind())
+	}
+}
+
+// Upsert inserts the data like Create, and updates the updateColumns of the existing record instead
+// when a unique key is duplicated, in a single statement:
+// INSERT ... ON DUPLICATE KEY UPDATE on MySQL, INSERT ... ON CONFLICT (conflictColumns) DO UPDATE on PostgreSQL and SQLite.
+// The updateColumns default to the inserted columns except the conflict columns, which default to the primary key
+// and are ignored by MySQL. It returns the affected rows of the database, MySQL counts an updated row as 2.
+func (m *Impl) Upsert(data any, updateColumns []string, conflictColumns ...string) (num int64, err error) {
+	if err = m.preCheck("Upsert", ctlFilter, ctlExclude, ctlWhere, ctlSelect, ctlOrderBy, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
+		return 0, err
+	}
+
+	rows, err := m.createRows(data)
+	if err != nil {
+		return 0, err
+	}
+	if len(rows) == 0 || len(rows[0]) == 0 {
+		return 0, errors.New("upsert " + DataEmptyError)
+	}
+	rows = m.createTimestamps(rows)
+
+	if len(conflictColumns) == 0 {
+		if m.pk == "" {
+			return 0, fmt.Errorf(PrimaryKeyError, "Upsert")
+		}
+		conflictColumns = []string{m.pk}
+	}
+	for _, column := range conflictColumns {
+		if _, ok := m.fieldNameMap[column]; !ok {
+			return 0, fmt.Errorf(ColumnNotExistError, column)
+		}
+	}
+	for _, column := range updateColumns {
+		if _, ok := m.fieldNameMap[column]; !ok {
+			return 0, fmt.Errorf(UpdateColumnNotExistError, column)
+		}
+	}
+
+	var (
+		columns  []string
+		keys     []string
+		defaults []string
+	)
+	conflicts := strSlice2Map(conflictColumns)
+	for _, k := range m.fieldNameSlice {
+		if _, ok := rows[0][k]; !ok {
+			continue
+		}
+		columns = append(columns, m.quote(k))
+		keys = append(keys, k)
+		// the autoCreateTime columns keep the time the existing record was created
+		if _, ok := conflicts[k]; !ok && !slices.Contains(m.createTimes, k) {
+			defaults = append(defaults, k)
+		}
+	}
+	if len(columns) == 0 {
+		return 0, errors.New("upsert " + DataEmptyError)
+	}
+	if len(updateColumns) == 0 {
+		updateColumns = defaults
+	} else {
+		updateColumns = slices.Clone(updateColumns)
+		for _, column := range m.updateTimes {
+			if !slices.Contains(updateColumns, column) {
+				updateColumns = append(updateColumns, column)
+			}
+		}
+	}
+	if len(updateColumns) == 0 {
+		return 0, errors.New(UpsertColumnsError)
+	}
+
+	upsertSQL := m.operator.UpsertSQL(quoteFieldNames(conflictColumns, m.operator.GetQuote()), quoteFieldNames(updateColumns, m.operator.GetQuote()))
+	if upsertSQL == "" {
+		return 0, fmt.Errorf(OperatorNotSupportedError, "Upsert")
+	}
+
+	sql := fmt.Sprintf(InsertTemp, m.operator.GetTableName(), strings.Join(columns, ","), strings.Repeat("?,", len(columns)-1)+"?")
+
+	return m.operator.BulkInsert(m.ctx(), sql+upsertSQL, keys, rows)
 }
 
 // Remove deletes the records matching the current query set.
 // It returns the number of records deleted and any error encountered.
 // Note: This method will really remove records from the database
 func (m *Impl) Remove() (num int64, err error) {
-	if err = m.preCheck("Remove", ctlSelect, ctlGroupBy, ctlHaving); err != nil {
+	if err = m.preCheck("Remove", ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
 		return 0, err
 	}
 
 	sql := fmt.Sprintf(DeleteTemp, m.operator.GetTableName())
 
-	filterSQL, filterArgs := m.qs.GetQuerySet()
+	filterSQL, filterArgs := m.whereSQL()
 	sql += filterSQL
 
-	return m.operator.Remove(m.ctx(), sql, filterArgs...)
+	return m.deleteHooks(func() (int64, error) {
+		return m.operator.Remove(m.ctx(), sql, filterArgs...)
+	})
 }
 
 func (m *Impl) update(data map[string]any) (num int64, err error) {
 	if len(data) == 0 {
 		return 0, errors.New("update " + DataEmptyError)
 	}
+	data = m.updateTimestamps(data)
+
+	// the version column is increased by one, and the version in the data is the one the records must have,
+	// so the update of a record changed by someone else since it was read affects nothing
+	var version any
+	if m.version != "" {
+		if _, ok := data[m.version].(Expr); !ok {
+			version = data[m.version]
+			data = maps.Clone(data)
+			data[m.version] = F(m.version).Add(1)
+		}
+	}
+	// the stale check needs the affected rows, an operator which can not report them would always fail it
+	if version != nil && !m.operator.ReportsAffectedRows() {
+		return 0, fmt.Errorf(VersionNotSupportedError, "Update")
+	}
 
 	var (
 		args       []any
 		updateRows []string
-		updateArgs []any
 	)
 
 	for k, v := range data {
 		if _, ok := m.fieldNameMap[k]; !ok {
 			return 0, fmt.Errorf(UpdateColumnNotExistError, k)
 		}
-		updateRows = append(updateRows, "`"+k+"`")
-		updateArgs = append(updateArgs, v)
+		if column, ok := m.validateExpr(v); !ok {
+			return 0, fmt.Errorf(ColumnNotExistError, column)
+		}
+		// an expression is rendered in place, e.g. F("stock").Sub(1) gives `stock`=`stock` - ?
+		if expr, ok := v.(Expr); ok {
+			exprSQL, exprArgs := expr.SQL(m.quote)
+			updateRows = append(updateRows, m.quote(k)+"="+exprSQL)
+			args = append(args, exprArgs...)
+			continue
+		}
+		updateRows = append(updateRows, m.quote(k)+"=?")
+		args = append(args, v)
 	}
 
-	sql := fmt.Sprintf(UpdateTemp, m.operator.GetTableName(), strings.Join(updateRows, "=?,")+"=?")
-	args = append(args, updateArgs...)
+	sql := fmt.Sprintf(UpdateTemp, m.operator.GetTableName(), strings.Join(updateRows, ","))
 
-	filterSQL, filterArgs := m.qs.GetQuerySet()
-	sql += filterSQL
+	filterSQL, filterArgs := m.whereSQL()
 	args = append(args, filterArgs...)
+	if version != nil {
+		filterSQL = andWhere(filterSQL, m.quote(m.version)+"=?")
+		args = append(args, version)
+	}
+	sql += filterSQL
 
-	return m.operator.Update(m.ctx(), sql, args...)
+	if num, err = m.operator.Update(m.ctx(), sql, args...); err == nil && version != nil && num == 0 {
+		return 0, ErrStaleObject
+	}
+	return num, err
 }
 
 // Update updates the records matching the current query set with the provided data map.
+// If the model has a column tagged with version, it is increased by one, and if the data has it,
+// only the records still having that version are updated, ErrStaleObject is returned if there are none.
+// The check needs the affected rows, with an operator which does not report them (ClickHouse) an Update
+// with the version returns an error instead.
 // It returns the number of records updated and any error encountered.
 func (m *Impl) Update(data map[string]any) (num int64, err error) {
-	if err = m.preCheckData("Update", data, ctlSelect, ctlGroupBy, ctlHaving); err != nil {
+	if err = m.preCheckData("Update", data, ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
+		return 0, err
+	}
+
+	data, model, err := m.beforeUpdate(data)
+	if err != nil {
+		return 0, err
+	}
+
+	if num, err = m.update(data); err != nil {
+		return num, err
+	}
+
+	return num, callHook(m, AfterUpdateHook.AfterUpdate, model)
+}
+
+// UpdateByPK updates the record of the primary key with the data like Update.
+func (m *Impl) UpdateByPK(pk any, data map[string]any) (num int64, err error) {
+	if err = m.filterPK("UpdateByPK", pk); err != nil {
+		return 0, err
+	}
+
+	return m.Update(data)
+}
+
+// setTimestamps sets the columns of the model struct v to the current time, only the zero ones if onlyZero.
+func (m *Impl) setTimestamps(v reflect.Value, columns []string, onlyZero bool) {
+	now := m.now()
+	for _, column := range columns {
+		if field := fieldByColumn(v, m.operator.GetDBTag(), column); field.IsValid() && (!onlyZero || field.IsZero()) {
+			setField(field, m.timestamp(column, now))
+		}
+	}
+}
+
+// Save inserts the model of modelPtr like Create if its primary key is zero, and writes the generated id back,
+// or else updates the columns of the record which differ from the model, it does nothing if none differs.
+// The version column is checked like Update, and the auto timestamps and the version are written back too.
+// It returns the number of records inserted or updated, and ErrNotFound if the record to update does not exist.
+func (m *Impl) Save(modelPtr any) (num int64, err error) {
+	if err = m.preCheck("Save", ctlFilter, ctlExclude, ctlWhere, ctlSelect, ctlOrderBy, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
+		return 0, err
+	}
+	if m.pk == "" {
+		return 0, fmt.Errorf(PrimaryKeyError, "Save")
+	}
+
+	rv := reflect.ValueOf(modelPtr)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return 0, errors.New(ModelTypeNotStructError)
+	}
+
+	tag := m.operator.GetDBTag()
+	pk := modelStruct2Map(modelPtr, tag)[m.pk]
+	if isZeroValue(pk) {
+		if err = callHook(m, BeforeCreateHook.BeforeCreate, modelPtr); err != nil {
+			return 0, err
+		}
+		m.setTimestamps(rv.Elem(), slices.Concat(m.createTimes, m.updateTimes), true)
+		if _, err = m.createModel(modelPtr); err != nil {
+			return 0, err
+		}
+		return 1, callHook(m, AfterCreateHook.AfterCreate, modelPtr)
+	}
+
+	if err = m.filterPK("Save", pk); err != nil {
+		return 0, err
+	}
+	query, args := m.buildQuery(m.fieldRows)
+	stored := reflect.New(rv.Elem().Type()).Interface()
+	if err = m.operator.FindOne(m.ctx(), stored, query+" LIMIT 1", args...); err != nil {
+		return 0, err
+	}
+	if err = callHook(m, BeforeUpdateHook.BeforeUpdate, modelPtr); err != nil {
+		return 0, err
+	}
+
+	data, storedData := modelStruct2Map(modelPtr, tag), modelStruct2Map(stored, tag)
+	changed := make(map[string]any, len(data))
+	for column, value := range data {
+		if column == m.pk || column == m.version || slices.Contains(m.createTimes, column) || slices.Contains(m.updateTimes, column) {
+			continue
+		}
+		if !sameValue(value, storedData[column]) {
+			changed[column] = value
+		}
+	}
+	if len(changed) == 0 {
+		return 0, nil
+	}
+
+	m.setTimestamps(rv.Elem(), m.updateTimes, false)
+	data = modelStruct2Map(modelPtr, tag)
+	for _, column := range slices.Concat(m.updateTimes, []string{m.version}) {
+		if value, ok := data[column]; ok {
+			changed[column] = value
+		}
+	}
+
+	if num, err = m.update(changed); err != nil {
+		return 0, err
+	}
+
+	if field := fieldByColumn(rv.Elem(), tag, m.version); m.version != "" && field.IsValid() {
+		switch {
+		case field.CanInt():
+			field.SetInt(field.Int() + 1)
+		case field.CanUint():
+			field.SetUint(field.Uint() + 1)
+		}
+	}
+	return num, callHook(m, AfterUpdateHook.AfterUpdate, modelPtr)
+}
+
+// BatchSize sets the number of rows BulkUpdate sends in one statement, 500 by default.
+func (m *Impl) BatchSize(size int) Controller {
+	if size <= 0 {
+		m.setError(BatchSizeError)
+		return m
+	}
+	m.batchSize = size
+	return m
+}
+
+// bulkUpdate updates the rows of one batch in a single statement, each column is set by a CASE of the key column.
+// A row without a column keeps its value by the ELSE of the CASE.
+func (m *Impl) bulkUpdate(keyColumn string, rows []map[string]any) (num int64, err error) {
+	var (
+		key        = m.quote(keyColumn)
+		updateRows []string
+		args       []any
+		keys       []any
+	)
+
+	for _, column := range m.fieldNameSlice {
+		if column == keyColumn {
+			continue
+		}
+
+		var (
+			cases    strings.Builder
+			caseArgs []any
+		)
+		for _, row := range rows {
+			v, ok := row[column]
+			if !ok {
+				continue
+			}
+			if expr, ok := v.(Expr); ok {
+				exprSQL, exprArgs := expr.SQL(m.quote)
+				cases.WriteString(" WHEN ? THEN " + exprSQL)
+				caseArgs = append(append(caseArgs, row[keyColumn]), exprArgs...)
+				continue
+			}
+			cases.WriteString(" WHEN ? THEN ?")
+			caseArgs = append(caseArgs, row[keyColumn], v)
+		}
+		if len(caseArgs) == 0 {
+			continue
+		}
+
+		column = m.quote(column)
+		updateRows = append(updateRows, column+"=CASE "+key+cases.String()+" ELSE "+column+" END")
+		args = append(args, caseArgs...)
+	}
+	if len(updateRows) == 0 {
+		return 0, errors.New("bulk update " + DataEmptyError)
+	}
+
+	now := m.now()
+	for _, column := range m.updateTimes {
+		if !slices.ContainsFunc(rows, func(row map[string]any) bool { _, ok := row[column]; return ok }) {
+			updateRows = append(updateRows, m.quote(column)+"=?")
+			args = append(args, m.timestamp(column, now))
+		}
+	}
+
+	for _, row := range rows {
+		keys = append(keys, row[keyColumn])
+	}
+
+	sql := fmt.Sprintf(UpdateTemp, m.operator.GetTableName(), strings.Join(updateRows, ","))
+
+	filterSQL, filterArgs := m.whereSQL()
+	sql += andWhere(filterSQL, key+" IN ("+strings.Repeat("?,", len(keys)-1)+"?)")
+	args = append(append(args, filterArgs...), keys...)
+
+	return m.operator.Update(m.ctx(), sql, args...)
+}
+
+// BulkUpdate updates many records with different values, each row has the keyColumn to find its record
+// and the columns to update, e.g. BulkUpdate("id", []map[string]any{{"id": 1, "status": 2}, {"id": 2, "status": 3}}) gives
+//
+//	UPDATE t SET `status`=CASE `id` WHEN ? THEN ? WHEN ? THEN ? ELSE `status` END WHERE `id` IN (?,?)
+//
+// The rows are sent in batches of BatchSize, the current Filter is added to the WHERE as a guard.
+// It returns the total number of records updated, use WithSession to update all batches in a transaction.
+func (m *Impl) BulkUpdate(keyColumn string, rows []map[string]any) (num int64, err error) {
+	if err = m.preCheck("BulkUpdate", ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
 		return 0, err
 	}
+	if len(rows) == 0 {
+		return 0, errors.New("bulk update " + DataEmptyError)
+	}
+	if _, ok := m.fieldNameMap[keyColumn]; !ok {
+		return 0, fmt.Errorf(ColumnNotExistError, keyColumn)
+	}
+	for i, row := range rows {
+		if _, ok := row[keyColumn]; !ok {
+			return 0, fmt.Errorf(BulkUpdateKeyError, i, keyColumn)
+		}
+		for k, v := range row {
+			if _, ok := m.fieldNameMap[k]; !ok {
+				return 0, fmt.Errorf(UpdateColumnNotExistError, k)
+			}
+			if column, ok := m.validateExpr(v); !ok {
+				return 0, fmt.Errorf(ColumnNotExistError, column)
+			}
+		}
+	}
+
+	size := m.batchSize
+	if size == 0 {
+		size = defaultBatchSize
+	}
 
-	return m.update(data)
+	for batch := range slices.Chunk(rows, size) {
+		n, err := m.bulkUpdate(keyColumn, batch)
+		if err != nil {
+			return num, err
+		}
+		num += n
+	}
+
+	return num, nil
 }
 
 // Count retrieves the total number of records matching the current query set.
@@ -556,14 +1833,85 @@ func (m *Impl) Count() (num int64, err error) {
 		return num, err
 	}
 
-	filterSQL, filterArgs := m.qs.GetQuerySet()
+	filterSQL, filterArgs := m.joinWhereSQL()
 
 	return m.operator.Count(m.ctx(), filterSQL, filterArgs...)
 }
 
+// CountDistinct retrieves the number of distinct non-NULL values of the column in the records matching the current query set.
+func (m *Impl) CountDistinct(column string) (num int64, err error) {
+	if err = m.preCheck("CountDistinct"); err != nil {
+		return num, err
+	}
+	if !m.validateColumn(column) {
+		return num, fmt.Errorf(ColumnNotExistError, column)
+	}
+
+	filterSQL, filterArgs := m.joinWhereSQL()
+
+	return m.operator.CountDistinct(m.ctx(), m.quote(column), filterSQL, filterArgs...)
+}
+
+// Aggregate computes the aggregations over all the records matching the current query set,
+// e.g. Aggregate(Sum("amount"), Avg("age").As("age")) returns {"amount__sum": ..., "age": ...}.
+func (m *Impl) Aggregate(aggregations ...Aggregation) (result map[string]any, err error) {
+	if err = m.preCheck("Aggregate", ctlSelect, ctlOrderBy, ctlLimit, ctlGroupBy, ctlHaving, ctlAnnotate, ctlDistinct); err != nil {
+		return result, err
+	}
+	if !m.validateAggregations(aggregations) {
+		return result, m.haveError()
+	}
+
+	query, args, columns := m.aggregationQuery(nil, aggregations)
+
+	res := reflect.New(resultStructType(columns, m.operator.GetDBTag())).Interface()
+
+	if err = m.operator.FindOne(m.ctx(), res, query, args...); err != nil {
+		return map[string]any{}, err
+	}
+
+	return resultStruct2Map(res, columns), nil
+}
+
+// selectQuery returns the query of FindOneModel and FindAllModel, it has the annotations if Annotate was called.
+func (m *Impl) selectQuery() (query string, args []any, err error) {
+	if m.hasCalled(ctlAnnotate) {
+		query, args, _, err = m.annotatedQuery()
+		return query, args, err
+	}
+
+	query, args = m.buildQuery(m.qs.GetSelectSQL())
+	return query, args, nil
+}
+
+func (m *Impl) findAnnotated(one bool) (result []map[string]any, err error) {
+	query, args, columns, err := m.annotatedQuery()
+	if err != nil {
+		return []map[string]any{}, err
+	}
+
+	res := reflect.New(reflect.SliceOf(resultStructType(columns, m.operator.GetDBTag()))).Interface()
+
+	if one {
+		query += " LIMIT 1"
+	} else {
+		query += m.qs.GetLimitSQL()
+	}
+	query += m.lockSQL()
+
+	if err = m.operator.FindAll(m.ctx(), res, query, args...); err != nil {
+		return []map[string]any{}, err
+	}
+	if !one && m.hasCalled(ctlBefore) {
+		reverseSlice(res)
+	}
+
+	return resultStructSlice2MapSlice(res, columns), nil
+}
+
 func (m *Impl) findOne() (result map[string]any, err error) {
 	query, args := m.buildQuery(m.qs.GetSelectSQL())
-	query += " LIMIT 1"
+	query += " LIMIT 1" + m.lockSQL()
 
 	res := deepCopyModelPtrStructure(m.modelPtr)
 
@@ -588,6 +1936,17 @@ func (m *Impl) findOne() (result map[string]any, err error) {
 // FindOne retrieves a single record matching the current query set into a map.
 // It returns the data as a map, or an error if the operation fails.
 func (m *Impl) FindOne() (result map[string]any, err error) {
+	if m.hasCalled(ctlAnnotate) {
+		if err = m.preCheck("FindOne"); err != nil {
+			return result, err
+		}
+		rows, err := m.findAnnotated(true)
+		if err != nil || len(rows) == 0 {
+			return map[string]any{}, err
+		}
+		return rows[0], nil
+	}
+
 	if err = m.preCheck("FindOne", ctlHaving); err != nil {
 		return result, err
 	}
@@ -610,15 +1969,50 @@ func (m *Impl) FindOneModel(modelPtr any) (err error) {
 		return fmt.Errorf(ModelTypeNotStructError)
 	}
 
-	query, args := m.buildQuery(m.qs.GetSelectSQL())
-	query += " LIMIT 1"
+	query, args, err := m.selectQuery()
+	if err != nil {
+		return err
+	}
+	query += " LIMIT 1" + m.lockSQL()
 
-	return m.operator.FindOne(m.ctx(), modelPtr, query, args...)
+	if err = m.operator.FindOne(m.ctx(), modelPtr, query, args...); err != nil {
+		return err
+	}
+
+	return m.findHook(modelPtr)
+}
+
+// filterPK adds the filter of the primary key value.
+func (m *Impl) filterPK(opName string, pk any) error {
+	if m.pk == "" {
+		return fmt.Errorf(PrimaryKeyError, opName)
+	}
+
+	m.setCalled(ctlFilter)
+	m.qs.FilterToSQL(queryset.NotNot, queryset.Cond{m.pk: pk})
+	return nil
+}
+
+// Get finds the record of the primary key like FindOne, it returns an empty map if there is none.
+// The primary key is the column tagged with pk, e.g. `db:"uid,pk"`, or the id column.
+func (m *Impl) Get(pk any) (result map[string]any, err error) {
+	if err = m.filterPK("Get", pk); err != nil {
+		return map[string]any{}, err
+	}
+
+	return m.FindOne()
 }
 
 // FindAll retrieves all records matching the current query set into a slice of maps.
 // It returns the data as a slice of maps, or an error if the operation fails.
 func (m *Impl) FindAll() (result []map[string]any, err error) {
+	if m.hasCalled(ctlAnnotate) {
+		if err = m.preCheck("FindAll"); err != nil {
+			return result, err
+		}
+		return m.findAnnotated(false)
+	}
+
 	if err = m.preCheck("FindAll", ctlHaving); err != nil {
 		return result, err
 	}
@@ -627,7 +2021,7 @@ func (m *Impl) FindAll() (result []map[string]any, err error) {
 	}
 
 	query, args := m.buildQuery(m.qs.GetSelectSQL())
-	query += m.qs.GetLimitSQL()
+	query += m.qs.GetLimitSQL() + m.lockSQL()
 
 	res := deepCopyModelPtrStructure(m.modelSlicePtr)
 
@@ -636,6 +2030,9 @@ func (m *Impl) FindAll() (result []map[string]any, err error) {
 	if err != nil {
 		return []map[string]any{}, err
 	}
+	if m.hasCalled(ctlBefore) {
+		reverseSlice(res)
+	}
 
 	result = modelStructSlice2MapSlice(res, m.operator.GetDBTag())
 
@@ -660,26 +2057,392 @@ func (m *Impl) FindAllModel(modelSlicePtr any) (err error) {
 		return fmt.Errorf(ModelTypeNotSliceError)
 	}
 
-	query, args := m.buildQuery(m.qs.GetSelectSQL())
-	query += m.qs.GetLimitSQL()
+	query, args, err := m.selectQuery()
+	if err != nil {
+		return err
+	}
+	query += m.qs.GetLimitSQL() + m.lockSQL()
+
+	if err = m.operator.FindAll(m.ctx(), modelSlicePtr, query, args...); err != nil {
+		return err
+	}
+	if m.hasCalled(ctlBefore) {
+		reverseSlice(modelSlicePtr)
+	}
+
+	return m.findHook(modelSlicePtr)
+}
+
+// pluck finds the column of the records matching the current query set into a slice of structs,
+// whose only field has the type typ, and returns the slice.
+func (m *Impl) pluck(opName, column string, typ reflect.Type) (rows reflect.Value, err error) {
+	if err = m.preCheck(opName, ctlSelect, ctlAnnotate); err != nil {
+		return rows, err
+	}
+	if !m.validateColumn(column) {
+		return rows, fmt.Errorf(ColumnNotExistError, column)
+	}
+
+	columns := []resultColumn{{name: unqualifiedName(column), typ: typ}}
+
+	query, args := m.buildQuery(m.quote(column))
+	query += m.qs.GetLimitSQL() + m.lockSQL()
 
-	err = m.operator.FindAll(m.ctx(), modelSlicePtr, query, args...)
+	res := reflect.New(reflect.SliceOf(resultStructType(columns, m.operator.GetDBTag())))
+
+	if err = m.operator.FindAll(m.ctx(), res.Interface(), query, args...); err != nil {
+		return rows, err
+	}
+	if m.hasCalled(ctlBefore) {
+		reverseSlice(res.Interface())
+	}
+
+	return res.Elem(), nil
+}
+
+// Pluck retrieves the values of a single column of the records matching the current query set.
+// The values have the type of the model field, or nil for NULL. Combined with Distinct it returns the distinct values.
+func (m *Impl) Pluck(column string) (values []any, err error) {
+	rows, err := m.pluck("Pluck", column, m.columnType(column))
+	if err != nil {
+		return []any{}, err
+	}
+
+	values = make([]any, rows.Len())
+	for i := range values {
+		values[i] = resultValue(rows.Index(i).Field(0))
+	}
+
+	return values, nil
+}
+
+// PluckAs is like Pluck, but scans the column directly into a slice of T, e.g. PluckAs[int64](ctl(ctx), "id").
+// T should be a pointer or a sql.Null* type if the column can be NULL.
+func PluckAs[T any](ctl Controller, column string) ([]T, error) {
+	m, ok := ctl.(*Impl)
+	if !ok {
+		return nil, fmt.Errorf(ControllerTypeError, "PluckAs", ctl)
+	}
+
+	rows, err := m.pluck("PluckAs", column, reflect.TypeFor[T]())
+	if err != nil {
+		return []T{}, err
+	}
+
+	values := make([]T, rows.Len())
+	for i := range values {
+		values[i] = rows.Index(i).Field(0).Interface().(T)
+	}
+
+	return values, nil
+}
+
+// each streams the rows of the FindAll query one by one into modelPtr and calls yield after each row,
+// the iteration stops when yield returns false or the context is done.
+func (m *Impl) each(query string, args []any, modelPtr any, yield func() bool) error {
+	err := m.operator.FindEach(m.ctx(), modelPtr, func() error {
+		if err := m.ctx().Err(); err != nil {
+			return err
+		}
+		if !yield() {
+			return errStopIteration
+		}
+		return nil
+	}, query+m.qs.GetLimitSQL()+m.lockSQL(), args...)
+	if errors.Is(err, errStopIteration) {
+		return nil
+	}
 	return err
 }
 
-// Delete marks the records as deleted by setting the 'is_deleted' field to true.
+// Rows streams the records matching the current query set as maps, like FindAll does without loading all of them.
+// An error ends the iteration, e.g.
+//
+//	for row, err := range ctl(ctx).Filter(Cond{"type": 1}).Rows() {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func (m *Impl) Rows() iter.Seq2[map[string]any, error] {
+	return func(yield func(map[string]any, error) bool) {
+		if m.hasCalled(ctlAnnotate) {
+			if err := m.preCheck("Rows", ctlBefore); err != nil {
+				yield(nil, err)
+				return
+			}
+			query, args, columns, err := m.annotatedQuery()
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			res := reflect.New(resultStructType(columns, m.operator.GetDBTag()))
+			if err = m.each(query, args, res.Interface(), func() bool {
+				return yield(resultStruct2Map(res.Interface(), columns), nil)
+			}); err != nil {
+				yield(nil, err)
+			}
+			return
+		}
+
+		if err := m.preCheck("Rows", ctlHaving, ctlBefore); err != nil {
+			yield(nil, err)
+			return
+		}
+		if m.hasCalled(ctlSelect) && hasSelectAlias(m.qs.GetSelectSQL()) {
+			yield(nil, fmt.Errorf(SelectAliasNotSupportedError, "Rows", "Iter"))
+			return
+		}
+
+		query, args := m.buildQuery(m.qs.GetSelectSQL())
+		res := deepCopyModelPtrStructure(m.modelPtr)
+		if err := m.each(query, args, res, func() bool {
+			row := modelStruct2Map(res, m.operator.GetDBTag())
+			if m.hasCalled(ctlSelect) {
+				row = filterBySelectColumns(row, m.qs.GetSelectSQL())
+			}
+			reflect.ValueOf(res).Elem().SetZero()
+			return yield(row, nil)
+		}); err != nil {
+			yield(nil, err)
+		}
+	}
+}
+
+// Iter streams the records matching the current query set into T, like FindAllModel does without loading all of them.
+// T must be a struct, an error ends the iteration, e.g.
+//
+//	for source, err := range norm.Iter[Source](ctl(ctx).Filter(Cond{"type": 1})) {
+//		if err != nil {
+//			return err
+//		}
+//	}
+func Iter[T any](ctl Controller) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+
+		m, ok := ctl.(*Impl)
+		if !ok {
+			yield(zero, fmt.Errorf(ControllerTypeError, "Iter", ctl))
+			return
+		}
+		if err := m.preCheck("Iter", ctlBefore); err != nil {
+			yield(zero, err)
+			return
+		}
+		if reflect.TypeFor[T]().Kind() != reflect.Struct {
+			yield(zero, errors.New(ModelTypeNotStructError))
+			return
+		}
+
+		query, args, err := m.selectQuery()
+		if err != nil {
+			yield(zero, err)
+			return
+		}
+
+		model := new(T)
+		if err = m.each(query, args, model, func() bool {
+			row := *model
+			*model = zero
+			return yield(row, nil)
+		}); err != nil {
+			yield(zero, err)
+		}
+	}
+}
+
+// chunk finds the records matching the current query set in chunks of size ordered by the primary key,
+// each chunk is found by find and seeks from the last id of the previous one, so fn can update or delete
+// the records it has seen without skipping others. It stops at the first error or the last chunk.
+func (m *Impl) chunk(opName string, size int, find func(query string, args []any) (num int, lastID any, err error), fn func() error) error {
+	if err := m.preCheck(opName, ctlSelect, ctlOrderBy, ctlLimit, ctlGroupBy, ctlHaving, ctlAnnotate, ctlDistinct, ctlAfter, ctlBefore); err != nil {
+		return err
+	}
+	if size <= 0 {
+		return fmt.Errorf(ChunkSizeError, opName)
+	}
+	if m.pk == "" {
+		return fmt.Errorf(PrimaryKeyError, opName)
+	}
+
+	selectRows, key := m.fieldRows, m.quote(m.pk)
+	if m.hasCalled(ctlJoin) {
+		selectRows, key = m.qualifiedFieldRows(), m.quote(m.tableName+"."+m.pk)
+	}
+
+	joinSQL, joinArgs := m.qs.GetJoinSQL()
+	base := fmt.Sprintf(SelectTemp, selectRows, m.operator.GetTableName()) + joinSQL
+	filterSQL, filterArgs := m.whereSQL()
+	filterArgs = slices.Concat(joinArgs, filterArgs)
+	orderLimit := " ORDER BY " + key + " ASC LIMIT " + strconv.Itoa(size) + m.lockSQL()
+
+	var lastID any
+	for {
+		if err := m.ctx().Err(); err != nil {
+			return err
+		}
+
+		query, args := base+filterSQL, append([]any{}, filterArgs...)
+		if lastID != nil {
+			query = base + andWhere(filterSQL, key+" > ?")
+			args = append(args, lastID)
+		}
+
+		num, last, err := find(query+orderLimit, args)
+		if err != nil || num == 0 {
+			return err
+		}
+		if err = fn(); err != nil {
+			return err
+		}
+		if num < size {
+			return nil
+		}
+		lastID = last
+	}
+}
+
+// Chunk calls fn with the records matching the current query set in chunks of size, ordered by the primary key, e.g.
+//
+//	err := ctl(ctx).Filter(Cond{"type": 1}).Chunk(500, func(rows []map[string]any) error {
+//		return backfill(rows)
+//	})
+//
+// Each chunk is found by WHERE pk > last instead of OFFSET, so fn may update or delete the rows it gets.
+// It stops and returns the error of fn, the model must have a primary key, see Get.
+func (m *Impl) Chunk(size int, fn func(rows []map[string]any) error) (err error) {
+	var rows []map[string]any
+	return m.chunk("Chunk", size, func(query string, args []any) (int, any, error) {
+		res := deepCopyModelPtrStructure(m.modelSlicePtr)
+		if err := m.operator.FindAll(m.ctx(), res, query, args...); err != nil {
+			return 0, nil, err
+		}
+		rows = modelStructSlice2MapSlice(res, m.operator.GetDBTag())
+		if len(rows) == 0 {
+			return 0, nil, nil
+		}
+		return len(rows), rows[len(rows)-1][m.pk], nil
+	}, func() error {
+		return fn(rows)
+	})
+}
+
+// ChunkModel is like Chunk, but finds each chunk into the slice of modelSlicePtr before calling fn.
+func (m *Impl) ChunkModel(size int, modelSlicePtr any, fn func() error) (err error) {
+	rv := reflect.ValueOf(modelSlicePtr)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf(ModelTypeNotSliceError)
+	}
+
+	return m.chunk("ChunkModel", size, func(query string, args []any) (int, any, error) {
+		rv.Elem().SetZero()
+		if err := m.operator.FindAll(m.ctx(), modelSlicePtr, query, args...); err != nil {
+			return 0, nil, err
+		}
+		num := rv.Elem().Len()
+		if num == 0 {
+			return 0, nil, nil
+		}
+		return num, modelStruct2Map(rv.Elem().Index(num-1).Interface(), m.operator.GetDBTag())[m.pk], nil
+	}, fn)
+}
+
+// QuerySQL returns the SELECT statement of the current query set and its args without running it.
+// It lets the controller be the value of the exists lookup of another controller, see ColumnSQL for the in lookup.
+func (m *Impl) QuerySQL() (query string, args []any, err error) {
+	if err = m.preCheck("QuerySQL"); err != nil {
+		return "", nil, err
+	}
+
+	if query, args, err = m.selectQuery(); err != nil {
+		return "", nil, err
+	}
+	query += m.qs.GetLimitSQL()
+
+	return query, args, nil
+}
+
+// ColumnSQL returns the SELECT statement like QuerySQL, it must select a single column,
+// as it is compared with a column by the in lookup, e.g. Cond{"id__in": sourceCli(ctx).Select([]string{"id"})}.
+func (m *Impl) ColumnSQL() (query string, args []any, err error) {
+	if query, args, err = m.QuerySQL(); err != nil {
+		return "", nil, err
+	}
+	if columns := m.selectCount(); columns != 1 {
+		return "", nil, fmt.Errorf(SubqueryColumnsError, columns)
+	}
+
+	return query, args, nil
+}
+
+// selectCount returns the number of the columns the query selects.
+func (m *Impl) selectCount() int {
+	if m.hasCalled(ctlAnnotate) {
+		columns := m.selectColumns
+		if !m.hasCalled(ctlSelect) {
+			columns = m.groupColumns
+		}
+		return len(columns) + len(m.annotations)
+	}
+
+	selectSQL := m.qs.GetSelectSQL()
+	if selectSQL == "" || selectSQL == Asterisk {
+		return len(m.fieldNameSlice)
+	}
+	return len(splitSelectClause(selectSQL))
+}
+
+// Delete marks the records as deleted by setting the soft delete column to true, or to the current time
+// if it is a timestamp like deleted_at, see newSoftDelete.
 // It returns the number of records marked as deleted.
 // Note: This method is not a true delete operation; it only marks records as deleted.
 func (m *Impl) Delete() (num int64, err error) {
-	if err = m.preCheck("Delete", ctlSelect, ctlGroupBy, ctlHaving); err != nil {
+	if err = m.preCheck("Delete", ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
 		return 0, err
 	}
+	if m.softDelete.column == "" {
+		return 0, fmt.Errorf(SoftDeleteColumnError, "Delete")
+	}
 
-	return m.update(map[string]any{"is_deleted": true})
+	var deleted any = true
+	if m.softDelete.timestamp {
+		deleted = m.now()
+	}
+	return m.deleteHooks(func() (int64, error) {
+		return m.update(map[string]any{m.softDelete.column: deleted})
+	})
+}
+
+// DeleteByPK marks the record of the primary key as deleted like Delete.
+func (m *Impl) DeleteByPK(pk any) (num int64, err error) {
+	if err = m.filterPK("DeleteByPK", pk); err != nil {
+		return 0, err
+	}
+
+	return m.Delete()
+}
+
+// Restore undoes Delete on the deleted records matching the current query set,
+// the soft delete column is set back to false, or to NULL if it is a timestamp.
+// It returns the number of records restored.
+func (m *Impl) Restore() (num int64, err error) {
+	if err = m.preCheck("Restore", ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
+		return 0, err
+	}
+	if m.softDelete.column == "" {
+		return 0, fmt.Errorf(SoftDeleteColumnError, "Restore")
+	}
+
+	var restored any = false
+	if m.softDelete.timestamp {
+		restored = nil
+	}
+	m.setCalled(ctlOnlyDeleted)
+	return m.update(map[string]any{m.softDelete.column: restored})
 }
 
 func (m *Impl) exist() (exist bool, err error) {
-	filterSQL, filterArgs := m.qs.GetQuerySet()
+	filterSQL, filterArgs := m.joinWhereSQL()
 
 	return m.operator.Exist(m.ctx(), filterSQL, filterArgs...)
 }
@@ -713,7 +2476,7 @@ func (m *Impl) List() (total int64, data []map[string]any, err error) {
 
 // GetOrCreate creates a new record if it does not already exist, or returns the existing record.
 func (m *Impl) GetOrCreate(data map[string]any) (res map[string]any, err error) {
-	if err = m.preCheckData("GetOrCreate", data, ctlSelect, ctlGroupBy, ctlHaving); err != nil {
+	if err = m.preCheckData("GetOrCreate", data, ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
 		return res, err
 	}
 
@@ -731,7 +2494,7 @@ func (m *Impl) GetOrCreate(data map[string]any) (res map[string]any, err error)
 
 // CreateOrUpdate creates a new record if it does not already exist, or updates the existing record.
 func (m *Impl) CreateOrUpdate(data map[string]any) (created bool, numOrID int64, err error) {
-	if err = m.preCheckData("CreateOrUpdate", data, ctlSelect, ctlGroupBy, ctlHaving); err != nil {
+	if err = m.preCheckData("CreateOrUpdate", data, ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
 		return false, 0, err
 	}
 
@@ -759,7 +2522,7 @@ func (m *Impl) CreateOrUpdate(data map[string]any) (created bool, numOrID int64,
 // If data not exist, it will create a new record and return the 'id' column value(if exists) and created true.
 // if data exist, it will return 0 and created false
 func (m *Impl) CreateIfNotExist(data map[string]any) (id int64, created bool, err error) {
-	if err = m.preCheckData("CreateIfNotExist", data, ctlSelect, ctlGroupBy, ctlHaving); err != nil {
+	if err = m.preCheckData("CreateIfNotExist", data, ctlSelect, ctlGroupBy, ctlHaving, ctlJoin, ctlAnnotate, ctlDistinct); err != nil {
 		return 0, false, err
 	}
 
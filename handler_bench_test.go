@@ -52,10 +52,26 @@ func (op *benchOperator) OperatorSQL(operator, method string) string {
 	}
 }
 
+func (op *benchOperator) LockSQL(lock string) string {
+	return ""
+}
+
+func (op *benchOperator) InsertSQL(mode string) string {
+	return ""
+}
+
+func (op *benchOperator) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return ""
+}
+
 func (op *benchOperator) GetPlaceholder() string {
 	return op.placeholder
 }
 
+func (op *benchOperator) GetQuote() string {
+	return "`"
+}
+
 func (op *benchOperator) GetDBTag() string {
 	return op.dbTag
 }
@@ -69,6 +85,14 @@ func (op *benchOperator) SetTableName(tableName string) ioperator.Operator {
 	return op
 }
 
+func (op *benchOperator) SetPrimaryKey(column string) ioperator.Operator {
+	return op
+}
+
+func (op *benchOperator) ReportsAffectedRows() bool {
+	return true
+}
+
 func (op *benchOperator) WithSession(session any) ioperator.Operator {
 	// Session is ignored in benchmarks.
 	return op
@@ -94,6 +118,10 @@ func (op *benchOperator) Count(ctx context.Context, condition string, args ...an
 	return 100, nil
 }
 
+func (op *benchOperator) CountDistinct(ctx context.Context, column, condition string, args ...any) (int64, error) {
+	return 10, nil
+}
+
 func (op *benchOperator) Exist(ctx context.Context, condition string, args ...any) (bool, error) {
 	return true, nil
 }
@@ -108,6 +136,10 @@ func (op *benchOperator) FindAll(ctx context.Context, model any, query string, a
 	return nil
 }
 
+func (op *benchOperator) FindEach(ctx context.Context, model any, fn func() error, query string, args ...any) error {
+	return nil
+}
+
 // Benchmarks
 
 // BenchmarkHandler_Create_Struct measures Create with a struct input (using reflection + Struct2Map).
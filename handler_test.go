@@ -2,9 +2,11 @@ package norm
 
 import (
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -13,7 +15,11 @@ import (
 	"github.com/zeromicro/go-zero/core/stores/sqlx"
 
 	"github.com/leisurelicht/norm/internal/queryset"
+	clickhouse_go "github.com/leisurelicht/norm/operator/clickhouse/clickhouse-go"
 	go_zero "github.com/leisurelicht/norm/operator/mysql/go-zero"
+	sqlx_op "github.com/leisurelicht/norm/operator/mysql/sqlx"
+	pg_op "github.com/leisurelicht/norm/operator/postgres/sqlx"
+	sqlite_op "github.com/leisurelicht/norm/operator/sqlite/sqlx"
 	"github.com/leisurelicht/norm/test"
 )
 
@@ -213,6 +219,83 @@ func TestGoZeroMysqlTransaction_Refactored(t *testing.T) {
 			t.Error("got exist, want not exist")
 		}
 	})
+
+	t.Run("with session for update", func(t *testing.T) {
+		err := conn.Transact(func(tx sqlx.Session) error {
+			var jobs []test.Source
+			if err := sourceCli(ctx).WithSession(tx).Filter(Cond{"type": 1}).OrderBy([]string{"id"}).Limit(2, 1).
+				ForUpdate().SkipLocked().FindAllModel(&jobs); err != nil {
+				return err
+			}
+			if len(jobs) != 2 || jobs[0].Id != 11 || jobs[1].Id != 21 {
+				return fmt.Errorf("got jobs %+v", jobs)
+			}
+			res, err := sourceCli(ctx).WithSession(tx).Filter(Cond{"id": 11}).ForUpdate().FindOne()
+			if err != nil {
+				return err
+			}
+			if res["id"] != int64(11) {
+				return fmt.Errorf("got %v", res)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Transaction error: %v", err)
+		}
+	})
+
+	t.Run("with session stream", func(t *testing.T) {
+		err := conn.Transact(func(tx sqlx.Session) error {
+			cli := sourceCli(ctx).WithSession(tx)
+			if _, err := cli.Create(map[string]any{"id": 2003, "name": "session_s", "description": "session tx"}); err != nil {
+				return err
+			}
+
+			var ids []int64
+			for source, err := range Iter[test.Source](sourceCli(ctx).WithSession(tx).
+				Filter(Cond{"id__in": []int{11, 2003}}).OrderBy([]string{"id"}).ForUpdate()) {
+				if err != nil {
+					return err
+				}
+				ids = append(ids, source.Id)
+			}
+			if !reflect.DeepEqual(ids, []int64{11, 2003}) {
+				return fmt.Errorf("got ids %v within tx", ids)
+			}
+
+			var names []any
+			for row, err := range sourceCli(ctx).WithSession(tx).Filter(Cond{"id": 2003}).Rows() {
+				if err != nil {
+					return err
+				}
+				names = append(names, row["name"])
+			}
+			if !reflect.DeepEqual(names, []any{"session_s"}) {
+				return fmt.Errorf("got names %v within tx", names)
+			}
+			return errors.New("force rollback")
+		})
+		if err == nil || err.Error() != "force rollback" {
+			t.Fatalf("got error %v, want force rollback", err)
+		}
+
+		exist, err := sourceCli(ctx).Filter(Cond{"id": 2003}).Exist()
+		if err != nil {
+			t.Fatalf("Exist error: %v", err)
+		}
+		if exist {
+			t.Error("got exist, want not exist")
+		}
+	})
+
+	t.Run("with session not streamable", func(t *testing.T) {
+		var err error
+		for _, err = range sourceCli(ctx).WithSession(struct{ sqlx.Session }{}).Rows() {
+		}
+		if !errors.Is(err, go_zero.ErrSessionNotStreamable) {
+			t.Errorf("got error %v, want %v", err, go_zero.ErrSessionNotStreamable)
+		}
+	})
 }
 
 // TestGoZeroMysqlMethods_Refactored 优化后的方法测试
@@ -224,7 +307,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 	ctx := context.Background()
 
 	t.Run("Count", func(t *testing.T) {
-		num, err := sourceCli(nil).Count()
+		num, err := sourceCli(nil).WithDeleted().Count()
 		if err != nil {
 			t.Fatalf("Count error: %v", err)
 		}
@@ -482,7 +565,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 	})
 
 	t.Run("Contains filter", func(t *testing.T) {
-		res, err := sourceCli(ctx).Filter(Cond{"name__contains": []string{"Ac", "Ap"}}).OrderBy("id").Limit(10, 1).FindAll()
+		res, err := sourceCli(ctx).WithDeleted().Filter(Cond{"name__contains": []string{"Ac", "Ap"}}).OrderBy("id").Limit(10, 1).FindAll()
 		if err != nil {
 			t.Fatalf("FindAll error: %v", err)
 		}
@@ -492,7 +575,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 	})
 
 	t.Run("Not contains and Exclude", func(t *testing.T) {
-		resNotContains, err := sourceCli(ctx).Filter(Cond{"name__not_contains": []string{"Ac", "Ap"}}).OrderBy("id").Limit(10, 1).FindAll()
+		resNotContains, err := sourceCli(ctx).WithDeleted().Filter(Cond{"name__not_contains": []string{"Ac", "Ap"}}).OrderBy("id").Limit(10, 1).FindAll()
 		if err != nil {
 			t.Fatalf("FindAll error: %v", err)
 		}
@@ -500,7 +583,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 			t.Errorf("got len %d, want 9", len(resNotContains))
 		}
 
-		resExclude, err := sourceCli(ctx).Exclude(Cond{"name__contains": []string{"Ac", "Ap"}}).OrderBy("id").Limit(10, 1).FindAll()
+		resExclude, err := sourceCli(ctx).WithDeleted().Exclude(Cond{"name__contains": []string{"Ac", "Ap"}}).OrderBy("id").Limit(10, 1).FindAll()
 		if err != nil {
 			t.Fatalf("FindAll error: %v", err)
 		}
@@ -573,7 +656,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 		groupbyNames := []struct {
 			Name string `db:"name"`
 		}{}
-		if err := sourceCli(ctx).Select([]string{"name"}).GroupBy("name").FindAllModel(&groupbyNames); err != nil {
+		if err := sourceCli(ctx).WithDeleted().Select([]string{"name"}).GroupBy("name").FindAllModel(&groupbyNames); err != nil {
 			t.Fatalf("GroupBy error: %v", err)
 		}
 		if len(groupbyNames) != 5 {
@@ -588,7 +671,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 		groupbyNames1 := []struct {
 			Name string `db:"name"`
 		}{}
-		if err := sourceCli(ctx).Select([]string{"name"}).GroupBy([]string{"name"}).FindAllModel(&groupbyNames1); err != nil {
+		if err := sourceCli(ctx).WithDeleted().Select([]string{"name"}).GroupBy([]string{"name"}).FindAllModel(&groupbyNames1); err != nil {
 			t.Fatalf("GroupBy error: %v", err)
 		}
 		if len(groupbyNames1) != 5 {
@@ -603,7 +686,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 		groupbyNames2 := []struct {
 			Name string `db:"name"`
 		}{}
-		if err := sourceCli(ctx).Select("name").GroupBy([]string{}).FindAllModel(&groupbyNames2); err != nil {
+		if err := sourceCli(ctx).WithDeleted().Select("name").GroupBy([]string{}).FindAllModel(&groupbyNames2); err != nil {
 			t.Fatalf("GroupBy error: %v", err)
 		}
 		if len(groupbyNames2) != 15 {
@@ -617,7 +700,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 	})
 
 	t.Run("CRUD with map", func(t *testing.T) {
-		t.Cleanup(func() { sourceCli(ctx).Filter(Cond{"id": 666}).Remove() })
+		t.Cleanup(func() { sourceCli(ctx).WithDeleted().Filter(Cond{"id": 666}).Remove() })
 
 		if _, err := sourceCli(ctx).Create(map[string]any{"id": 666, "name": "666", "description": "2333"}); err != nil {
 			t.Fatalf("Create error: %v", err)
@@ -649,7 +732,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 	})
 
 	t.Run("Delete soft delete", func(t *testing.T) {
-		t.Cleanup(func() { sourceCli(ctx).Filter(Cond{"id": 666}).Remove() })
+		t.Cleanup(func() { sourceCli(ctx).WithDeleted().Filter(Cond{"id": 666}).Remove() })
 
 		if _, err := sourceCli(ctx).Create(map[string]any{"id": 666, "name": "666", "description": "2333"}); err != nil {
 			t.Fatalf("Create error: %v", err)
@@ -669,7 +752,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 			t.Errorf("got deleted %d, want 1", num)
 		}
 
-		res, err := sourceCli(ctx).Filter(Cond{"id": 666, "is_deleted": true}).FindOne()
+		res, err := sourceCli(ctx).OnlyDeleted().Filter(Cond{"id": 666}).FindOne()
 		if err != nil {
 			t.Fatalf("FindOne error: %v", err)
 		}
@@ -680,7 +763,7 @@ func TestGoZeroMysqlMethods_Refactored(t *testing.T) {
 			t.Errorf("got name=%s, want test", res["name"])
 		}
 
-		if _, err := sourceCli(ctx).Filter(Cond{"id": 666, "is_deleted": true}).Remove(); err != nil {
+		if _, err := sourceCli(ctx).OnlyDeleted().Filter(Cond{"id": 666}).Remove(); err != nil {
 			t.Fatalf("Remove error: %v", err)
 		}
 
@@ -1101,28 +1184,6 @@ func TestGoZeroMysqlHandlerError_Refactored(t *testing.T) {
 	ctl := NewController(go_zero.NewOperator(sqlx.NewMysql(getMysqlAddress())), test.Source{})
 	ctx := context.Background()
 
-	t.Run("FilterOrWhereError", func(t *testing.T) {
-		tests := []struct {
-			name    string
-			fn      func() error
-			wantErr string
-		}{
-			{"Filter with Where", func() error { _, err := ctl(nil).Filter(Cond{}).Where("").FindOne(); return err }, fmt.Sprintf(queryset.FilterOrWhereError, "Filter")},
-			{"Exclude with Where", func() error { _, err := ctl(ctx).Exclude(Cond{}).Where("").FindOne(); return err }, fmt.Sprintf(queryset.FilterOrWhereError, "Exclude")},
-		}
-		for _, tt := range tests {
-			t.Run(tt.name, func(t *testing.T) {
-				err := tt.fn()
-				if err == nil {
-					t.Fatalf("expected error %q, got nil", tt.wantErr)
-				}
-				if err.Error() != tt.wantErr {
-					t.Errorf("got %q, want %q", err.Error(), tt.wantErr)
-				}
-			})
-		}
-	})
-
 	t.Run("FindOne unsupported", func(t *testing.T) {
 		tests := []struct {
 			name    string
@@ -1774,3 +1835,2628 @@ func TestGoZeroMysqlHandlerError_Refactored(t *testing.T) {
 		}
 	})
 }
+
+func TestSqlxMysqlMethods(t *testing.T) {
+	db, err := sqlx_op.NewMysql(getMysqlAddress())
+	if err != nil {
+		t.Fatalf("NewMysql error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	sourceCli := NewController(sqlx_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlx_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	t.Run("Count", func(t *testing.T) {
+		num, err := sourceCli(ctx).WithDeleted().Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 15 {
+			t.Errorf("got %d, want 15", num)
+		}
+	})
+
+	t.Run("FindOneModel", func(t *testing.T) {
+		var got test.Source
+		if err := sourceCli(ctx).Filter(Cond{"id": 11}).FindOneModel(&got); err != nil {
+			t.Fatalf("FindOneModel error: %v", err)
+		}
+		if got.Id != 11 || got.Name != "Acfun" || got.Description != "A 站" {
+			t.Errorf("got %+v", got)
+		}
+		if got.CreateTime.IsZero() {
+			t.Error("got zero create_time")
+		}
+
+		err := sourceCli(ctx).Filter(Cond{"id": 12345}).FindOneModel(&got)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("got error %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		res, err := sourceCli(ctx).Filter(Cond{"is_deleted": false}).Select([]string{"id", "name"}).OrderBy("id").Limit(10, 1).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		if len(res) != 9 {
+			t.Fatalf("got len %d, want 9", len(res))
+		}
+		if res[0]["id"].(int64) != 11 || len(res[0]) != 2 {
+			t.Errorf("got first row %v", res[0])
+		}
+	})
+
+	t.Run("GroupBy alias", func(t *testing.T) {
+		var groups []struct {
+			Name  string `db:"name"`
+			Total int64  `db:"total"`
+		}
+		if err := sourceCli(ctx).WithDeleted().Select("name, COUNT(1) AS total").GroupBy([]string{"name"}).FindAllModel(&groups); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		if len(groups) != 5 {
+			t.Fatalf("got len %d, want 5", len(groups))
+		}
+		for _, g := range groups {
+			if g.Total != 3 {
+				t.Errorf("got total %d for %s, want 3", g.Total, g.Name)
+			}
+		}
+	})
+
+	t.Run("CRUD", func(t *testing.T) {
+		t.Cleanup(func() { _, _ = sourceCli(ctx).Filter(Cond{"id__in": []int{3001, 3002, 3003}}).Remove() })
+
+		if _, err := sourceCli(ctx).Create(map[string]any{"id": 3001, "name": "sqlx", "description": "sqlx"}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if _, err := sourceCli(ctx).Create(map[string]any{"id": 3001, "name": "sqlx", "description": "sqlx"}); !errors.Is(err, ErrDuplicateKey) {
+			t.Errorf("got error %v, want %v", err, ErrDuplicateKey)
+		}
+
+		num, err := sourceCli(ctx).Create([]map[string]any{
+			{"id": 3002, "name": "sqlx_bulk", "description": "sqlx"},
+			{"id": 3003, "name": "sqlx_bulk", "description": "sqlx"},
+		})
+		if err != nil {
+			t.Fatalf("bulk Create error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got created %d, want 2", num)
+		}
+
+		num, err = sourceCli(ctx).Filter(Cond{"name": "sqlx_bulk"}).Update(map[string]any{"description": "updated"})
+		if err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got updated %d, want 2", num)
+		}
+
+		num, err = sourceCli(ctx).Filter(Cond{"id__in": []int{3001, 3002, 3003}}).Remove()
+		if err != nil {
+			t.Fatalf("Remove error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got removed %d, want 3", num)
+		}
+	})
+
+	t.Run("Create auto increment id", func(t *testing.T) {
+		id, err := propertyCli(ctx).Create(map[string]any{"source_id": 11, "column_name": "sqlx", "description": "sqlx"})
+		if err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		t.Cleanup(func() { _, _ = propertyCli(ctx).Filter(Cond{"id": id}).Remove() })
+		if id <= 0 {
+			t.Errorf("got id %d, want > 0", id)
+		}
+	})
+
+	t.Run("WithSession rollback", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTx error: %v", err)
+		}
+		if _, err := sourceCli(ctx).WithSession(tx).Create(map[string]any{"id": 3004, "name": "sqlx_tx", "description": "sqlx"}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		exist, err := sourceCli(ctx).WithSession(tx).Filter(Cond{"id": 3004}).Exist()
+		if err != nil {
+			t.Fatalf("Exist error: %v", err)
+		}
+		if !exist {
+			t.Error("got not exist within tx, want exist")
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback error: %v", err)
+		}
+
+		exist, err = sourceCli(ctx).Filter(Cond{"id": 3004}).Exist()
+		if err != nil {
+			t.Fatalf("Exist error: %v", err)
+		}
+		if exist {
+			t.Error("got exist after rollback, want not exist")
+		}
+	})
+}
+
+// mysqlOperators returns the go-zero and the database/sql operators of the test mysql database.
+func mysqlOperators(t *testing.T) []struct {
+	name string
+	op   Operator
+} {
+	db, err := sqlx_op.NewMysql(getMysqlAddress())
+	if err != nil {
+		t.Fatalf("NewMysql error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+
+	return []struct {
+		name string
+		op   Operator
+	}{
+		{"go-zero", go_zero.NewOperator(go_zero.NewMysql(getMysqlAddress()))},
+		{"sqlx", sqlx_op.NewOperator(db)},
+	}
+}
+
+func TestMysqlSoftDelete(t *testing.T) {
+	for _, tt := range mysqlOperators(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceCli := NewController(tt.op, test.Source{})
+			ctx := context.Background()
+			t.Cleanup(func() { _, _ = sourceCli(ctx).WithDeleted().Filter(Cond{"name": "mysql_soft"}).Remove() })
+
+			if _, err := sourceCli(ctx).Create([]map[string]any{
+				{"id": 4001, "name": "mysql_soft", "description": "kept", "is_deleted": false},
+				{"id": 4002, "name": "mysql_soft", "description": "deleted", "is_deleted": true},
+			}); err != nil {
+				t.Fatalf("Create error: %v", err)
+			}
+
+			count := func(t *testing.T, ctl Controller, want int64) {
+				t.Helper()
+				num, err := ctl.Filter(Cond{"name": "mysql_soft"}).Count()
+				if err != nil {
+					t.Fatalf("Count error: %v", err)
+				}
+				if num != want {
+					t.Errorf("got %d, want %d", num, want)
+				}
+			}
+			count(t, sourceCli(ctx), 1)
+			count(t, sourceCli(ctx).WithDeleted(), 2)
+			count(t, sourceCli(ctx).OnlyDeleted(), 1)
+
+			num, err := sourceCli(ctx).Filter(Cond{"name": "mysql_soft"}).Update(map[string]any{"description": "updated"})
+			if err != nil {
+				t.Fatalf("Update error: %v", err)
+			}
+			if num != 1 {
+				t.Errorf("got updated %d, want 1", num)
+			}
+			got, err := PluckAs[string](sourceCli(ctx).WithDeleted().Filter(Cond{"name": "mysql_soft"}).OrderBy([]string{"id"}), "description")
+			if err != nil {
+				t.Fatalf("PluckAs error: %v", err)
+			}
+			if want := []string{"updated", "deleted"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+
+			if num, err = sourceCli(ctx).Filter(Cond{"id": 4002}).Remove(); err != nil {
+				t.Fatalf("Remove error: %v", err)
+			}
+			if num != 0 {
+				t.Errorf("got removed %d, want 0", num)
+			}
+			count(t, sourceCli(ctx).OnlyDeleted(), 1)
+
+			if num, err = sourceCli(ctx).Filter(Cond{"id": 4001}).Delete(); err != nil || num != 1 {
+				t.Fatalf("Delete got %d, %v", num, err)
+			}
+			count(t, sourceCli(ctx), 0)
+			if num, err = sourceCli(ctx).Filter(Cond{"id": 4001}).Restore(); err != nil || num != 1 {
+				t.Fatalf("Restore got %d, %v", num, err)
+			}
+			count(t, sourceCli(ctx), 1)
+
+			if num, err = sourceCli(ctx).OnlyDeleted().Filter(Cond{"name": "mysql_soft"}).Remove(); err != nil || num != 1 {
+				t.Fatalf("Remove got %d, %v", num, err)
+			}
+			count(t, sourceCli(ctx).WithDeleted(), 1)
+		})
+	}
+}
+
+func TestMysqlUpsert(t *testing.T) {
+	for _, tt := range mysqlOperators(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceCli := NewController(tt.op, test.Source{})
+			ctx := context.Background()
+			t.Cleanup(func() { _, _ = sourceCli(ctx).Filter(Cond{"id__in": []int{4101, 4102}}).Remove() })
+
+			names := func(t *testing.T) []string {
+				t.Helper()
+				got, err := PluckAs[string](sourceCli(ctx).Filter(Cond{"id__in": []int{4101, 4102}}).OrderBy([]string{"id"}), "name")
+				if err != nil {
+					t.Fatalf("PluckAs error: %v", err)
+				}
+				return got
+			}
+
+			num, err := sourceCli(ctx).Upsert(map[string]any{"id": 4101, "name": "upsert", "type": 1, "description": "new"}, nil)
+			if err != nil {
+				t.Fatalf("Upsert error: %v", err)
+			}
+			if num != 1 {
+				t.Errorf("got %d, want 1", num)
+			}
+
+			// ON DUPLICATE KEY UPDATE counts an updated row as 2
+			if num, err = sourceCli(ctx).Upsert(map[string]any{"id": 4101, "name": "upsert2", "type": 1, "description": "changed"}, []string{"name"}); err != nil {
+				t.Fatalf("Upsert error: %v", err)
+			}
+			if num != 2 {
+				t.Errorf("got %d, want 2", num)
+			}
+			res, err := sourceCli(ctx).Filter(Cond{"id": 4101}).FindOne()
+			if err != nil {
+				t.Fatalf("FindOne error: %v", err)
+			}
+			if res["name"] != "upsert2" || res["description"] != "new" {
+				t.Errorf("got %v", res)
+			}
+
+			if num, err = sourceCli(ctx).Upsert([]map[string]any{
+				{"id": 4101, "name": "bulk1", "type": 1, "description": "bulk"},
+				{"id": 4102, "name": "bulk2", "type": 1, "description": "bulk"},
+			}, []string{"name", "description"}); err != nil {
+				t.Fatalf("Upsert error: %v", err)
+			}
+			if num != 3 {
+				t.Errorf("got %d, want 3", num)
+			}
+			if got, want := names(t), []string{"bulk1", "bulk2"}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestMysqlBulkUpdate(t *testing.T) {
+	for _, tt := range mysqlOperators(t) {
+		t.Run(tt.name, func(t *testing.T) {
+			sourceCli := NewController(tt.op, test.Source{})
+			ctx := context.Background()
+			ids := []int{4201, 4202, 4203}
+			t.Cleanup(func() { _, _ = sourceCli(ctx).WithDeleted().Filter(Cond{"id__in": ids}).Remove() })
+
+			if _, err := sourceCli(ctx).Create([]map[string]any{
+				{"id": 4201, "name": "bulk_update", "type": 1, "description": "a", "is_deleted": false},
+				{"id": 4202, "name": "bulk_update", "type": 2, "description": "b", "is_deleted": false},
+				{"id": 4203, "name": "bulk_update", "type": 3, "description": "c", "is_deleted": true},
+			}); err != nil {
+				t.Fatalf("Create error: %v", err)
+			}
+
+			num, err := sourceCli(ctx).BatchSize(2).BulkUpdate("id", []map[string]any{
+				{"id": 4201, "type": 7, "description": "a2"},
+				{"id": 4202, "type": F("type").Add(10)},
+				{"id": 4203, "description": "deleted"},
+			})
+			if err != nil {
+				t.Fatalf("BulkUpdate error: %v", err)
+			}
+			if num != 2 {
+				t.Errorf("got %d, want 2", num)
+			}
+
+			var sources []test.Source
+			if err = sourceCli(ctx).WithDeleted().Filter(Cond{"id__in": ids}).OrderBy([]string{"id"}).FindAllModel(&sources); err != nil {
+				t.Fatalf("FindAllModel error: %v", err)
+			}
+			got := [][2]any{}
+			for _, source := range sources {
+				got = append(got, [2]any{source.Type, source.Description})
+			}
+			if want := [][2]any{{int64(7), "a2"}, {int64(12), "b"}, {int64(3), "c"}}; !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+// newSqliteDB returns a sqlite database in a temp file loaded with test/ddl_sqlite.sql.
+// A file is used instead of ":memory:" so that every pooled connection sees the same data.
+func newSqliteDB(t *testing.T) *sql.DB {
+	t.Helper()
+	ddl, err := os.ReadFile("test/ddl_sqlite.sql")
+	if err != nil {
+		t.Fatalf("ReadFile error: %v", err)
+	}
+	db, err := sqlite_op.NewSqlite(filepath.Join(t.TempDir(), "test.db") + "?_pragma=busy_timeout(5000)")
+	if err != nil {
+		t.Fatalf("NewSqlite error: %v", err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	if _, err := db.Exec(string(ddl)); err != nil {
+		t.Fatalf("load ddl error: %v", err)
+	}
+	return db
+}
+
+func TestSqliteMethods(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlite_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	t.Run("Count", func(t *testing.T) {
+		num, err := sourceCli(ctx).Filter(Cond{"is_deleted": false}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 9 {
+			t.Errorf("got %d, want 9", num)
+		}
+	})
+
+	t.Run("FindOneModel", func(t *testing.T) {
+		var got test.Source
+		if err := sourceCli(ctx).Filter(Cond{"id": 11}).FindOneModel(&got); err != nil {
+			t.Fatalf("FindOneModel error: %v", err)
+		}
+		if got.Id != 11 || got.Name != "Acfun" || got.Description != "A 站" || got.IsDeleted {
+			t.Errorf("got %+v", got)
+		}
+		if got.CreateTime.IsZero() {
+			t.Error("got zero create_time")
+		}
+
+		err := sourceCli(ctx).Filter(Cond{"id": 12345}).FindOneModel(&got)
+		if !errors.Is(err, ErrNotFound) {
+			t.Errorf("got error %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("FindAll", func(t *testing.T) {
+		res, err := sourceCli(ctx).WithDeleted().Filter(Cond{"type": 1}).Select([]string{"id", "name"}).OrderBy("-id").Limit(2, 1).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		if len(res) != 2 {
+			t.Fatalf("got len %d, want 2", len(res))
+		}
+		if res[0]["id"].(int64) != 51 || res[1]["id"].(int64) != 41 || len(res[0]) != 2 {
+			t.Errorf("got %v", res)
+		}
+	})
+
+	t.Run("Lookups", func(t *testing.T) {
+		cases := []struct {
+			name string
+			cond Cond
+			want int64
+		}{
+			{"contains", Cond{"name__contains": "cfu"}, 3},
+			{"contains case sensitive", Cond{"name__contains": "CFU"}, 0},
+			{"icontains", Cond{"name__icontains": "CFU"}, 3},
+			{"startswith", Cond{"name__startswith": "A"}, 6},
+			{"startswith case sensitive", Cond{"name__startswith": "a"}, 0},
+			{"istartswith", Cond{"name__istartswith": "a"}, 6},
+			{"endswith", Cond{"name__endswith": "le"}, 6},
+			{"iendswith", Cond{"name__iendswith": "LE"}, 6},
+			{"not contains", Cond{"name__not_contains": "o"}, 9},
+			{"iexact", Cond{"name__iexact": "acfun"}, 3},
+			{"len", Cond{"name__len": 5}, 6},
+			{"in", Cond{"id__in": []int{11, 21, 99}}, 2},
+			{"between", Cond{"id__between": []int{20, 40}}, 6},
+			{"method", Cond{"create_time##toDateTime": "2024-05-16T17:33:25"}, 2},
+		}
+		for _, c := range cases {
+			t.Run(c.name, func(t *testing.T) {
+				num, err := sourceCli(ctx).WithDeleted().Filter(c.cond).Count()
+				if err != nil {
+					t.Fatalf("Count error: %v", err)
+				}
+				if num != c.want {
+					t.Errorf("got %d, want %d", num, c.want)
+				}
+			})
+		}
+	})
+
+	t.Run("GLOB wildcards are escaped", func(t *testing.T) {
+		t.Cleanup(func() { _, _ = sourceCli(ctx).Filter(Cond{"id__in": []int{4001, 4002}}).Remove() })
+		if _, err := sourceCli(ctx).Create([]map[string]any{
+			{"id": 4001, "name": "a*b?[c]", "description": "glob"},
+			{"id": 4002, "name": "axbyzc", "description": "glob"},
+		}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		for _, v := range []string{"*", "?", "[c]", "a*b?[c]"} {
+			num, err := sourceCli(ctx).Filter(Cond{"name__contains": v}).Count()
+			if err != nil {
+				t.Fatalf("Count error: %v", err)
+			}
+			if num != 1 {
+				t.Errorf("contains %q got %d, want 1", v, num)
+			}
+		}
+	})
+
+	t.Run("CRUD", func(t *testing.T) {
+		if _, err := sourceCli(ctx).Create(map[string]any{"id": 3001, "name": "sqlite", "description": "sqlite"}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if _, err := sourceCli(ctx).Create(map[string]any{"id": 3001, "name": "sqlite", "description": "sqlite"}); !errors.Is(err, ErrDuplicateKey) {
+			t.Errorf("got error %v, want %v", err, ErrDuplicateKey)
+		}
+
+		num, err := sourceCli(ctx).Create([]map[string]any{
+			{"id": 3002, "name": "sqlite_bulk", "description": "sqlite"},
+			{"id": 3003, "name": "sqlite_bulk", "description": "sqlite"},
+		})
+		if err != nil {
+			t.Fatalf("bulk Create error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got created %d, want 2", num)
+		}
+
+		num, err = sourceCli(ctx).Filter(Cond{"name": "sqlite_bulk"}).Update(map[string]any{"description": "updated"})
+		if err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got updated %d, want 2", num)
+		}
+
+		num, err = sourceCli(ctx).Filter(Cond{"id__in": []int{3001, 3002, 3003}}).Remove()
+		if err != nil {
+			t.Fatalf("Remove error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got removed %d, want 3", num)
+		}
+	})
+
+	t.Run("Create auto increment id", func(t *testing.T) {
+		id, err := propertyCli(ctx).Create(map[string]any{"source_id": 11, "column_name": "sqlite", "description": "sqlite"})
+		if err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if id != 46 {
+			t.Errorf("got id %d, want 46", id)
+		}
+	})
+
+	t.Run("WithSession rollback", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTx error: %v", err)
+		}
+		if _, err := sourceCli(ctx).WithSession(tx).Create(map[string]any{"id": 3004, "name": "sqlite_tx", "description": "sqlite"}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		exist, err := sourceCli(ctx).WithSession(tx).Filter(Cond{"id": 3004}).Exist()
+		if err != nil {
+			t.Fatalf("Exist error: %v", err)
+		}
+		if !exist {
+			t.Error("got not exist within tx, want exist")
+		}
+		if err := tx.Rollback(); err != nil {
+			t.Fatalf("Rollback error: %v", err)
+		}
+
+		exist, err = sourceCli(ctx).Filter(Cond{"id": 3004}).Exist()
+		if err != nil {
+			t.Fatalf("Exist error: %v", err)
+		}
+		if exist {
+			t.Error("got exist after rollback, want not exist")
+		}
+	})
+}
+
+func TestSqliteJoin(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlite_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	type report struct {
+		Id         int64  `db:"id"`
+		ColumnName string `db:"column_name"`
+		SourceName string `db:"source_name"`
+	}
+
+	t.Run("Join", func(t *testing.T) {
+		var got []report
+		err := propertyCli(ctx).
+			Join(sourceCli(ctx), Cond{"source_id": "id"}).
+			Filter(Cond{"source.name__contains": "Bili", "column_name": "title"}).
+			Select(`"property"."id", "property"."column_name", "source"."name" AS source_name`).
+			OrderBy([]string{"-source.id"}).
+			FindAllModel(&got)
+		if err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		want := []report{{16, "title", "Bilibili"}, {13, "title", "Bilibili"}, {10, "title", "Bilibili"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Count and Exist", func(t *testing.T) {
+		num, err := propertyCli(ctx).WithDeleted().LeftJoin(sourceCli(ctx).WithDeleted(), Cond{"source_id": "id"}).Filter(Cond{"source.is_deleted": true}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 18 {
+			t.Errorf("got %d, want 18", num)
+		}
+
+		exist, err := propertyCli(ctx).InnerJoin("source", Cond{"source_id": "id"}).Filter(Cond{"source.name": "Nothing"}).Exist()
+		if err != nil {
+			t.Fatalf("Exist error: %v", err)
+		}
+		if exist {
+			t.Error("got exist, want not exist")
+		}
+	})
+
+	t.Run("Soft deleted joined model", func(t *testing.T) {
+		apple := Cond{"source_id__in": []int{31, 32, 33}, "column_name": "title"}
+		tests := []struct {
+			name  string
+			table any
+			join  func(Controller, any, Cond) Controller
+			want  int64
+		}{
+			{"excluded", sourceCli(ctx), Controller.Join, 0},
+			{"left join keeps the main rows", sourceCli(ctx), Controller.LeftJoin, 3},
+			{"WithDeleted", sourceCli(ctx).WithDeleted(), Controller.Join, 3},
+			{"OnlyDeleted", sourceCli(ctx).OnlyDeleted(), Controller.Join, 3},
+			{"table name is not scoped", "source", Controller.Join, 3},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				num, err := tt.join(propertyCli(ctx).WithDeleted(), tt.table, Cond{"source_id": "id"}).Filter(apple).Count()
+				if err != nil {
+					t.Fatalf("Count error: %v", err)
+				}
+				if num != tt.want {
+					t.Errorf("got %d, want %d", num, tt.want)
+				}
+			})
+		}
+
+		var got []report
+		err := propertyCli(ctx).WithDeleted().LeftJoin(sourceCli(ctx), Cond{"source_id": "id"}).Filter(Cond{"id__in": []int{1, 19}}).
+			Select(`"property"."id", "property"."column_name", COALESCE("source"."name", '') AS source_name`).OrderBy([]string{"id"}).FindAllModel(&got)
+		if err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		want := []report{{1, "title", "Acfun"}, {19, "title", ""}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		num, err := propertyCli(ctx).Join(sourceCli(ctx).OnlyDeleted(), Cond{"source_id": "id"}).Filter(Cond{"source.name": "Acfun"}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 0 {
+			t.Errorf("got %d joined to the deleted Acfun, want 0", num)
+		}
+	})
+
+	t.Run("FindAll default columns", func(t *testing.T) {
+		res, err := propertyCli(ctx).Join(sourceCli(ctx), Cond{"source_id": "id"}).Filter(Cond{"source.type": 3, "id__lte": 9}).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		if len(res) != 3 || res[0]["source_id"].(int64) != 13 {
+			t.Errorf("got %v", res)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			call func() error
+			want string
+		}{
+			{"after filter", func() error {
+				_, err := propertyCli(ctx).Filter(Cond{"id": 1}).Join(sourceCli(ctx), Cond{"source_id": "id"}).FindAll()
+				return err
+			}, fmt.Sprintf(MustBeCalledBeforeError, "Join", "Filter")},
+			{"unknown on column", func() error {
+				_, err := propertyCli(ctx).Join(sourceCli(ctx), Cond{"source_id": "uid"}).FindAll()
+				return err
+			}, fmt.Sprintf(ColumnNotExistError, "source.uid")},
+			{"unknown filter column", func() error {
+				_, err := propertyCli(ctx).Join(sourceCli(ctx), Cond{"source_id": "id"}).Filter(Cond{"source.title": "A"}).FindAll()
+				return err
+			}, fmt.Sprintf(ColumnNotExistError, "source.title")},
+			{"not joined table", func() error {
+				_, err := propertyCli(ctx).Filter(Cond{"source.name": "A"}).FindAll()
+				return err
+			}, fmt.Sprintf(ColumnNotExistError, "source.name")},
+			{"unknown select column", func() error {
+				_, err := propertyCli(ctx).Join(sourceCli(ctx), Cond{"source_id": "id"}).Select([]string{"source.title"}).FindAll()
+				return err
+			}, fmt.Sprintf(SelectColumsValidateError, "[source.title] not exist")},
+			{"duplicate table", func() error {
+				_, err := propertyCli(ctx).Join("source", Cond{"source_id": "id"}).LeftJoin(sourceCli(ctx), Cond{"source_id": "id"}).FindAll()
+				return err
+			}, fmt.Sprintf(JoinTableDuplicateError, "source")},
+			{"empty on", func() error {
+				_, err := propertyCli(ctx).Join("source", Cond{}).FindAll()
+				return err
+			}, JoinOnEmptyError},
+			{"on type", func() error {
+				_, err := propertyCli(ctx).Join("source", Cond{"source_id": 1}).FindAll()
+				return err
+			}, fmt.Sprintf(JoinOnTypeError, "source_id", 1)},
+			{"table type", func() error {
+				_, err := propertyCli(ctx).Join(1, Cond{"source_id": "id"}).FindAll()
+				return err
+			}, fmt.Sprintf(JoinTableTypeError, 1)},
+			{"update", func() error {
+				_, err := propertyCli(ctx).Join("source", Cond{"source_id": "id"}).Update(map[string]any{"show_name": "A"})
+				return err
+			}, fmt.Sprintf(UnsupportedControllerError, "Join", "Update")},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if err := tt.call(); err == nil || err.Error() != tt.want {
+					t.Errorf("got error %v, want %s", err, tt.want)
+				}
+			})
+		}
+	})
+}
+
+func TestSqliteSubquery(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlite_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	t.Run("in", func(t *testing.T) {
+		num, err := propertyCli(ctx).WithDeleted().Filter(Cond{
+			"source_id__in": sourceCli(ctx).WithDeleted().Select([]string{"id"}).Filter(Cond{"name": "Apple"}),
+			"column_name":   "title",
+		}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got %d, want 3", num)
+		}
+	})
+
+	t.Run("not_in", func(t *testing.T) {
+		res, err := sourceCli(ctx).WithDeleted().Filter(Cond{
+			"id__not_in": propertyCli(ctx).Select([]string{"source_id"}).Filter(Cond{"is_deleted": false}),
+		}).Select([]string{"id"}).OrderBy([]string{"id"}).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		if len(res) != 6 || res[0]["id"].(int64) != 31 {
+			t.Errorf("got %v", res)
+		}
+	})
+
+	t.Run("exists", func(t *testing.T) {
+		num, err := sourceCli(ctx).Filter(Cond{
+			"property__exists": propertyCli(ctx).Select([]string{"id"}).Where(`"property"."source_id" = "source"."id" AND "property"."id" <= ?`, 6),
+		}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got %d, want 2", num)
+		}
+
+		num, err = sourceCli(ctx).WithDeleted().Exclude(Cond{
+			"property__exists": propertyCli(ctx).Select([]string{"id"}).Where(`"property"."source_id" = "source"."id" AND "property"."id" <= ?`, 6),
+		}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 13 {
+			t.Errorf("got %d, want 13", num)
+		}
+	})
+
+	t.Run("subquery error", func(t *testing.T) {
+		_, err := propertyCli(ctx).Filter(Cond{"source_id__in": sourceCli(ctx).Select([]string{"uid"})}).Count()
+		if err == nil || !strings.Contains(err.Error(), "uid") {
+			t.Errorf("got error %v, want select error of uid", err)
+		}
+
+		for sub, columns := range map[Controller]int{
+			sourceCli(ctx).Select([]string{"id", "name"}): 2,
+			sourceCli(ctx).Select("id, name"):             2,
+			sourceCli(ctx):                                7,
+		} {
+			_, err = propertyCli(ctx).Filter(Cond{"source_id__in": sub}).Count()
+			if want := fmt.Sprintf(SubqueryColumnsError, columns); err == nil || !strings.HasSuffix(err.Error(), want) {
+				t.Errorf("got error %v, want %s", err, want)
+			}
+		}
+
+		_, err = propertyCli(ctx).Filter(Cond{"source_id__in": "SELECT id FROM source"}).Count()
+		if err == nil || !strings.Contains(err.Error(), "raw string") {
+			t.Errorf("got error %v, want raw string error", err)
+		}
+	})
+}
+
+func TestSqliteExpr(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	t.Run("Filter", func(t *testing.T) {
+		num, err := sourceCli(ctx).WithDeleted().Filter(Cond{"update_time": F("create_time"), "id__gt": F("type").Mul(10).Add(20)}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 6 {
+			t.Errorf("got %d, want 6", num)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		num, err := sourceCli(ctx).Filter(Cond{"name": "Acfun"}).Update(map[string]any{"type": F("type").Add(10), "description": "updated"})
+		if err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got updated %d, want 3", num)
+		}
+
+		var got []test.Source
+		if err := sourceCli(ctx).Filter(Cond{"name": "Acfun"}).OrderBy([]string{"id"}).FindAllModel(&got); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		for i, s := range got {
+			if s.Type != int64(i+11) || s.Description != "updated" {
+				t.Errorf("got %+v", s)
+			}
+		}
+	})
+
+	t.Run("Unknown column", func(t *testing.T) {
+		injected := "type`; DROP TABLE source; --"
+		if _, err := sourceCli(ctx).Filter(Cond{"id__gt": F(injected)}).Count(); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, injected) {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Filter(Or(Cond{"name": "Acfun"}, Cond{"id": F("type").Add(F("unknown"))})).Count(); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, "unknown") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Filter(Cond{"name": "Acfun"}).Update(map[string]any{"type": F(injected)}); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, injected) {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).BulkUpdate("id", []map[string]any{{"id": 11, "type": F("unknown").Add(1)}}); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, "unknown") {
+			t.Errorf("got error %v", err)
+		}
+		if num, err := sourceCli(ctx).Count(); err != nil || num != 9 {
+			t.Errorf("got %d, %v", num, err)
+		}
+	})
+}
+
+// queryLogConn records the queries sent to the database.
+type queryLogConn struct {
+	*sql.DB
+	queries []string
+}
+
+func (c *queryLogConn) ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error) {
+	c.queries = append(c.queries, query)
+	return c.DB.ExecContext(ctx, query, args...)
+}
+
+func (c *queryLogConn) QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error) {
+	c.queries = append(c.queries, query)
+	return c.DB.QueryContext(ctx, query, args...)
+}
+
+func (c *queryLogConn) QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row {
+	c.queries = append(c.queries, query)
+	return c.DB.QueryRowContext(ctx, query, args...)
+}
+
+func TestSqlitePlaceholder(t *testing.T) {
+	conn := &queryLogConn{DB: newSqliteDB(t)}
+	sourceCli := NewController(sqlite_op.NewOperator(conn, sqlite_op.WithPlaceholder("$")), test.Source{})
+	ctx := context.Background()
+
+	num, err := sourceCli(ctx).Filter(Cond{"name": "Acfun", "type__in": []int{1, 2, 3}}).Count()
+	if err != nil {
+		t.Fatalf("Count error: %v", err)
+	}
+	if num != 3 {
+		t.Errorf("got %d, want 3", num)
+	}
+
+	id, err := sourceCli(ctx).Create(map[string]any{"name": "placeholder", "type": 1, "description": "created"})
+	if err != nil {
+		t.Fatalf("Create error: %v", err)
+	}
+	num, err = sourceCli(ctx).Filter(Cond{"id": id}).Update(map[string]any{"description": "updated"})
+	if err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if num != 1 {
+		t.Errorf("got updated %d, want 1", num)
+	}
+	got, err := sourceCli(ctx).Filter(Cond{"id": id, "name": "placeholder"}).FindOne()
+	if err != nil {
+		t.Fatalf("FindOne error: %v", err)
+	}
+	if got["description"] != "updated" {
+		t.Errorf("got %v", got)
+	}
+
+	for _, query := range conn.queries {
+		if strings.Contains(query, "?") || !strings.Contains(query, "$1") {
+			t.Errorf("query is not rebound: %s", query)
+		}
+	}
+}
+
+func TestSqliteQ(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		f    func() (int64, error)
+		want int64
+	}{
+		{"Filter", func() (int64, error) {
+			return sourceCli(ctx).Filter(Or(Cond{"name": "Acfun"}, And(Cond{"name": "Bilibili"}, Not(Cond{"type__in": []int{1, 2}})))).Count()
+		}, 4},
+		{"Filter with Cond", func() (int64, error) {
+			return sourceCli(ctx).Filter(Cond{"is_deleted": 0}, Or(Cond{"type": 1}, Cond{"type": 3})).Count()
+		}, 6},
+		{"Exclude", func() (int64, error) {
+			return sourceCli(ctx).Exclude(Or(Cond{"is_deleted": 1}, Cond{"type": 1})).Count()
+		}, 6},
+		{"Chain", func() (int64, error) {
+			return sourceCli(ctx).WithDeleted().Filter(Not(Cond{"name": "Apple"}).And(Cond{"type": 2}).Or(Cond{"id": 31})).Count()
+		}, 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num, err := tt.f()
+			if err != nil {
+				t.Fatalf("Count error: %v", err)
+			}
+			if num != tt.want {
+				t.Errorf("got %d, want %d", num, tt.want)
+			}
+		})
+	}
+
+	t.Run("Unknown qualified column", func(t *testing.T) {
+		_, err := sourceCli(ctx).Filter(And(Cond{"name": "Acfun"}, Or(Cond{"source.unknown": 1}))).Count()
+		if err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, "source.unknown") {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestSqliteWhere(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		f    func() (int64, error)
+		want int64
+	}{
+		{"Filter with Where", func() (int64, error) {
+			return sourceCli(ctx).Filter(Cond{"name": "Acfun"}).Where("type > ?", 1).Count()
+		}, 2},
+		{"Where with Filter", func() (int64, error) {
+			return sourceCli(ctx).Where("type > ?", 1).Filter(Cond{"name": "Acfun"}).Count()
+		}, 2},
+		{"Exclude with Where", func() (int64, error) {
+			return sourceCli(ctx).Exclude(Cond{"is_deleted": 1}).Where("length(name) = ?", 5).Count()
+		}, 3},
+		{"OrWhere", func() (int64, error) {
+			return sourceCli(ctx).WithDeleted().Filter(Cond{"name": "Acfun"}).OrWhere("id = ? OR id = ?", 31, 41).Count()
+		}, 5},
+		{"OrWhere with Filter", func() (int64, error) {
+			return sourceCli(ctx).WithDeleted().Filter(Cond{"name": "Acfun"}).OrWhere("id = ? OR id = ?", 31, 41).Filter(Cond{"type": 2}).Count()
+		}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			num, err := tt.f()
+			if err != nil {
+				t.Fatalf("Count error: %v", err)
+			}
+			if num != tt.want {
+				t.Errorf("got %d, want %d", num, tt.want)
+			}
+		})
+	}
+
+	t.Run("Args order", func(t *testing.T) {
+		var got []test.Source
+		err := sourceCli(ctx).Filter(Cond{"name__in": []string{"Acfun", "Bilibili"}}).Where("type = ?", 2).Exclude(Cond{"id": 12}).FindAllModel(&got)
+		if err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		if len(got) != 1 || got[0].Id != 22 {
+			t.Errorf("got %+v", got)
+		}
+	})
+}
+
+func TestSqliteAggregate(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlite_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	t.Run("Aggregate", func(t *testing.T) {
+		got, err := sourceCli(ctx).WithDeleted().Aggregate(Count("*"), Sum("type"), Avg("type"), Max("name"), Min("id").As("first"))
+		if err != nil {
+			t.Fatalf("Aggregate error: %v", err)
+		}
+		want := map[string]any{"count": int64(15), "type__sum": int64(30), "type__avg": 2.0, "name__max": "Microsoft", "first": int64(11)}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Aggregate with Filter", func(t *testing.T) {
+		got, err := sourceCli(ctx).Filter(Cond{"name": "Acfun"}).Exclude(Cond{"type": 3}).Aggregate(Sum("id"), Count("id"))
+		if err != nil {
+			t.Fatalf("Aggregate error: %v", err)
+		}
+		want := map[string]any{"id__sum": int64(23), "id__count": int64(2)}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Aggregate no rows", func(t *testing.T) {
+		got, err := sourceCli(ctx).Filter(Cond{"id": 0}).Aggregate(Count("*"), Sum("type"), Max("name"))
+		if err != nil {
+			t.Fatalf("Aggregate error: %v", err)
+		}
+		want := map[string]any{"count": int64(0), "type__sum": nil, "name__max": nil}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Annotate", func(t *testing.T) {
+		got, err := sourceCli(ctx).WithDeleted().GroupBy([]string{"name"}).Annotate(Count("*"), Sum("type").As("total")).OrderBy([]string{"name"}).Limit(2, 1).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		want := []map[string]any{
+			{"name": "Acfun", "count": int64(3), "total": int64(6)},
+			{"name": "Apple", "count": int64(3), "total": int64(6)},
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Annotate with Select and Having", func(t *testing.T) {
+		got, err := sourceCli(ctx).WithDeleted().Filter(Cond{"type__gt": 1}).Select([]string{"name"}).GroupBy([]string{"name"}).
+			Annotate(Max("id")).Having("MAX(id) > ?", 40).OrderBy([]string{"-name"}).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		want := []map[string]any{{"name": "Microsoft", "id__max": int64(53)}, {"name": "Google", "id__max": int64(43)}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Annotate FindOne", func(t *testing.T) {
+		got, err := sourceCli(ctx).GroupBy([]string{"is_deleted"}).Annotate(Count("*")).OrderBy([]string{"is_deleted"}).FindOne()
+		if err != nil {
+			t.Fatalf("FindOne error: %v", err)
+		}
+		want := map[string]any{"is_deleted": false, "count": int64(9)}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Annotate FindAllModel", func(t *testing.T) {
+		type sourceProperties struct {
+			Name  string `db:"name"`
+			Count int64  `db:"count"`
+		}
+		var got []sourceProperties
+		err := sourceCli(ctx).WithDeleted().Join(propertyCli(ctx).WithDeleted(), Cond{"id": "source_id"}).Filter(Cond{"property.column_name": "title"}).
+			GroupBy([]string{"source.name"}).Annotate(Count("property.id").As("count")).OrderBy([]string{"source.name"}).FindAllModel(&got)
+		if err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		want := []sourceProperties{{"Acfun", 3}, {"Apple", 3}, {"Bilibili", 3}, {"Google", 3}, {"Microsoft", 3}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name    string
+			fn      func() error
+			wantErr string
+		}{
+			{"empty", func() error { _, err := sourceCli(ctx).Aggregate(); return err }, AggregationEmptyError},
+			{"asterisk", func() error { _, err := sourceCli(ctx).Aggregate(Sum("*")); return err }, fmt.Sprintf(AggregationAsteriskError, "SUM")},
+			{"unknown column", func() error { _, err := sourceCli(ctx).Aggregate(Sum("age")); return err }, fmt.Sprintf(ColumnNotExistError, "age")},
+			{"duplicate alias", func() error { _, err := sourceCli(ctx).Aggregate(Sum("id"), Max("type").As("id__sum")); return err }, fmt.Sprintf(AggregationAliasError, "id__sum")},
+			{"GroupBy", func() error { _, err := sourceCli(ctx).GroupBy([]string{"name"}).Aggregate(Sum("id")); return err }, fmt.Sprintf(UnsupportedControllerError, "GroupBy", "Aggregate")},
+			{"Annotate without GroupBy", func() error { _, err := sourceCli(ctx).Annotate(Sum("id")).FindAll(); return err }, AnnotateColumnsError},
+			{"Annotate with string GroupBy", func() error { _, err := sourceCli(ctx).GroupBy("name").Annotate(Sum("id")).FindAll(); return err }, AnnotateColumnsError},
+			{"Annotate duplicate alias", func() error {
+				_, err := sourceCli(ctx).GroupBy([]string{"name"}).Annotate(Sum("id")).Annotate(Sum("id")).FindAll()
+				return err
+			}, fmt.Sprintf(AggregationAliasError, "id__sum")},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				err := tt.fn()
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("got error %v, want %s", err, tt.wantErr)
+				}
+			})
+		}
+	})
+}
+
+func TestSqliteDistinct(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlite_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	t.Run("FindAll", func(t *testing.T) {
+		got, err := sourceCli(ctx).Distinct("name").Filter(Cond{"is_deleted": false}).OrderBy([]string{"name"}).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		want := []map[string]any{{"name": "Acfun"}, {"name": "Bilibili"}, {"name": "Microsoft"}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("FindAllModel", func(t *testing.T) {
+		var got []test.Source
+		err := sourceCli(ctx).WithDeleted().Select([]string{"name", "is_deleted"}).Distinct().OrderBy([]string{"-name"}).Limit(2, 1).FindAllModel(&got)
+		if err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		want := []test.Source{{Name: "Microsoft"}, {Name: "Google", IsDeleted: true}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("CountDistinct", func(t *testing.T) {
+		tests := []struct {
+			name string
+			f    func() (int64, error)
+			want int64
+		}{
+			{"name", func() (int64, error) { return sourceCli(ctx).WithDeleted().CountDistinct("name") }, 5},
+			{"filtered", func() (int64, error) { return sourceCli(ctx).Filter(Cond{"is_deleted": false}).CountDistinct("name") }, 3},
+			{"joined", func() (int64, error) {
+				return propertyCli(ctx).Join(sourceCli(ctx), Cond{"source_id": "id"}).Filter(Cond{"source.name": "Acfun"}).CountDistinct("property.column_name")
+			}, 3},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				num, err := tt.f()
+				if err != nil {
+					t.Fatalf("CountDistinct error: %v", err)
+				}
+				if num != tt.want {
+					t.Errorf("got %d, want %d", num, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := sourceCli(ctx).CountDistinct("age"); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, "age") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Distinct("age").FindAll(); err == nil || err.Error() != fmt.Sprintf(SelectColumsValidateError, "[age] not exist") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Distinct().Update(map[string]any{"name": "x"}); err == nil || err.Error() != fmt.Sprintf(UnsupportedControllerError, "Distinct", "Update") {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestSqlitePluck(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlite_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	t.Run("Pluck", func(t *testing.T) {
+		got, err := sourceCli(ctx).Filter(Cond{"name": "Acfun"}).OrderBy([]string{"-id"}).Pluck("id")
+		if err != nil {
+			t.Fatalf("Pluck error: %v", err)
+		}
+		if want := []any{int64(13), int64(12), int64(11)}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Pluck distinct", func(t *testing.T) {
+		got, err := sourceCli(ctx).WithDeleted().Distinct().Filter(Cond{"is_deleted": true}).OrderBy([]string{"name"}).Pluck("name")
+		if err != nil {
+			t.Fatalf("Pluck error: %v", err)
+		}
+		if want := []any{"Apple", "Google"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Pluck empty", func(t *testing.T) {
+		got, err := sourceCli(ctx).Filter(Cond{"id": 0}).Pluck("id")
+		if err != nil {
+			t.Fatalf("Pluck error: %v", err)
+		}
+		if len(got) != 0 {
+			t.Errorf("got %v, want empty", got)
+		}
+	})
+
+	t.Run("PluckAs", func(t *testing.T) {
+		got, err := PluckAs[int64](sourceCli(ctx).WithDeleted().Filter(Cond{"type": 1}).OrderBy([]string{"id"}).Limit(3, 1), "id")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []int64{11, 21, 31}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("PluckAs joined", func(t *testing.T) {
+		got, err := PluckAs[string](propertyCli(ctx).Join(sourceCli(ctx), Cond{"source_id": "id"}).
+			Filter(Cond{"property.id__lte": 3}).OrderBy([]string{"property.id"}), "source.name")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []string{"Acfun", "Acfun", "Acfun"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := sourceCli(ctx).Pluck("age"); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, "age") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Select([]string{"id"}).Pluck("id"); err == nil || err.Error() != fmt.Sprintf(UnsupportedControllerError, "Select", "Pluck") {
+			t.Errorf("got error %v", err)
+		}
+		type wrapper struct{ Controller }
+		ctl := wrapper{sourceCli(ctx)}
+		if _, err := PluckAs[int64](ctl, "id"); err == nil || err.Error() != fmt.Sprintf(ControllerTypeError, "PluckAs", ctl) {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestSqliteStream(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	t.Run("Rows", func(t *testing.T) {
+		var ids []any
+		for row, err := range sourceCli(ctx).Filter(Cond{"name": "Acfun"}).OrderBy([]string{"id"}).Rows() {
+			if err != nil {
+				t.Fatalf("Rows error: %v", err)
+			}
+			ids = append(ids, row["id"])
+		}
+		if want := []any{int64(11), int64(12), int64(13)}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("Rows select", func(t *testing.T) {
+		for row, err := range sourceCli(ctx).Select([]string{"id", "name"}).Filter(Cond{"id": 21}).Rows() {
+			if err != nil {
+				t.Fatalf("Rows error: %v", err)
+			}
+			if want := map[string]any{"id": int64(21), "name": "Bilibili"}; !reflect.DeepEqual(row, want) {
+				t.Errorf("got %v, want %v", row, want)
+			}
+		}
+	})
+
+	t.Run("Rows annotated", func(t *testing.T) {
+		var rows []map[string]any
+		for row, err := range sourceCli(ctx).WithDeleted().Select([]string{"name"}).Annotate(Count("id")).
+			Filter(Cond{"type": 1}).GroupBy([]string{"name"}).OrderBy([]string{"name"}).Limit(2, 1).Rows() {
+			if err != nil {
+				t.Fatalf("Rows error: %v", err)
+			}
+			rows = append(rows, row)
+		}
+		want := []map[string]any{
+			{"name": "Acfun", "id__count": int64(1)},
+			{"name": "Apple", "id__count": int64(1)},
+		}
+		if !reflect.DeepEqual(rows, want) {
+			t.Errorf("got %v, want %v", rows, want)
+		}
+	})
+
+	t.Run("Iter", func(t *testing.T) {
+		var got []test.Source
+		for source, err := range Iter[test.Source](sourceCli(ctx).WithDeleted().Filter(Cond{"type": 2}).OrderBy([]string{"-id"})) {
+			if err != nil {
+				t.Fatalf("Iter error: %v", err)
+			}
+			got = append(got, source)
+		}
+		if len(got) != 5 || got[0].Id != 52 || got[0].Name != "Microsoft" || got[4].Id != 12 {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("Iter break", func(t *testing.T) {
+		n := 0
+		for _, err := range Iter[test.Source](sourceCli(ctx)) {
+			if err != nil {
+				t.Fatalf("Iter error: %v", err)
+			}
+			if n++; n == 2 {
+				break
+			}
+		}
+		if n != 2 {
+			t.Errorf("got %d rows, want 2", n)
+		}
+		// The rows of the stopped iteration are released.
+		if total, err := sourceCli(ctx).WithDeleted().Count(); err != nil || total != 15 {
+			t.Errorf("got %d, %v", total, err)
+		}
+	})
+
+	t.Run("Iter canceled", func(t *testing.T) {
+		cancelCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		var (
+			n       int
+			lastErr error
+		)
+		for _, err := range Iter[test.Source](sourceCli(cancelCtx)) {
+			if err != nil {
+				lastErr = err
+				break
+			}
+			n++
+			cancel()
+		}
+		if n != 1 || !errors.Is(lastErr, context.Canceled) {
+			t.Errorf("got %d rows, error %v", n, lastErr)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		for _, err := range sourceCli(ctx).Having("id > ?", 1).Rows() {
+			if err == nil || err.Error() != fmt.Sprintf(UnsupportedControllerError, "Having", "Rows") {
+				t.Errorf("got error %v", err)
+			}
+		}
+		for _, err := range Iter[int64](sourceCli(ctx)) {
+			if err == nil || err.Error() != ModelTypeNotStructError {
+				t.Errorf("got error %v", err)
+			}
+		}
+		type wrapper struct{ Controller }
+		ctl := wrapper{sourceCli(ctx)}
+		for _, err := range Iter[test.Source](ctl) {
+			if err == nil || err.Error() != fmt.Sprintf(ControllerTypeError, "Iter", ctl) {
+				t.Errorf("got error %v", err)
+			}
+		}
+	})
+}
+
+func TestSqliteKeyset(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	pageIds := func(rows []map[string]any) (ids []any) {
+		for _, row := range rows {
+			ids = append(ids, row["id"])
+		}
+		return ids
+	}
+
+	t.Run("After mixed order", func(t *testing.T) {
+		orderBy := []string{"type", "-id"}
+		all, err := sourceCli(ctx).OrderBy(orderBy).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+
+		var (
+			got    []any
+			cursor string
+		)
+		for range 10 {
+			ctl := sourceCli(ctx).OrderBy(orderBy)
+			if cursor != "" {
+				ctl = ctl.After(cursor)
+			}
+			rows, err := ctl.Limit(4, 1).FindAll()
+			if err != nil {
+				t.Fatalf("FindAll error: %v", err)
+			}
+			if len(rows) == 0 {
+				break
+			}
+			got = append(got, pageIds(rows)...)
+			if cursor, err = ctl.Cursor(rows[len(rows)-1]); err != nil {
+				t.Fatalf("Cursor error: %v", err)
+			}
+		}
+		if want := pageIds(all); !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("After with OrWhere", func(t *testing.T) {
+		query := func() Controller {
+			return sourceCli(ctx).WithDeleted().Where("type = ?", 1).OrWhere("name = ?", "Acfun").OrderBy([]string{"id"})
+		}
+		all, err := query().FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+
+		var (
+			got    []any
+			cursor string
+		)
+		for range 10 {
+			ctl := query()
+			if cursor != "" {
+				ctl = ctl.After(cursor)
+			}
+			rows, err := ctl.Limit(2, 1).FindAll()
+			if err != nil {
+				t.Fatalf("FindAll error: %v", err)
+			}
+			if len(rows) == 0 {
+				break
+			}
+			got = append(got, pageIds(rows)...)
+			if cursor, err = ctl.Cursor(rows[len(rows)-1]); err != nil {
+				t.Fatalf("Cursor error: %v", err)
+			}
+		}
+		if want := pageIds(all); len(want) < 4 || !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Before", func(t *testing.T) {
+		ctl := sourceCli(ctx).OrderBy([]string{"-name", "id"})
+		cursor, err := ctl.Cursor(map[string]any{"name": "Bilibili", "id": int64(22)})
+		if err != nil {
+			t.Fatalf("Cursor error: %v", err)
+		}
+		rows, err := sourceCli(ctx).WithDeleted().OrderBy([]string{"-name", "id"}).Before(cursor).Limit(3, 1).FindAll()
+		if err != nil {
+			t.Fatalf("FindAll error: %v", err)
+		}
+		if got, want := pageIds(rows), []any{int64(42), int64(43), int64(21)}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		// Apple and Acfun come after Bilibili in the descending name order.
+		if got, err := PluckAs[int64](sourceCli(ctx).WithDeleted().OrderBy([]string{"-name", "id"}).After(cursor), "id"); err != nil ||
+			!reflect.DeepEqual(got, []int64{23, 31, 32, 33, 11, 12, 13}) {
+			t.Errorf("got %v, %v", got, err)
+		}
+	})
+
+	t.Run("Model", func(t *testing.T) {
+		var first []test.Source
+		if err := sourceCli(ctx).Filter(Cond{"is_deleted": false}).OrderBy([]string{"name", "id"}).Limit(2, 1).FindAllModel(&first); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		ctl := sourceCli(ctx).Filter(Cond{"is_deleted": false}).OrderBy([]string{"name", "id"})
+		cursor, err := ctl.Cursor(first[len(first)-1])
+		if err != nil {
+			t.Fatalf("Cursor error: %v", err)
+		}
+
+		var next []test.Source
+		if err = ctl.After(cursor).Limit(2, 1).FindAllModel(&next); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		if len(next) != 2 || next[0].Id != 13 || next[1].Id != 21 {
+			t.Errorf("got %+v", next)
+		}
+
+		var prev []test.Source
+		if cursor, err = ctl.Cursor(&next[0]); err != nil {
+			t.Fatalf("Cursor error: %v", err)
+		}
+		if err = sourceCli(ctx).Filter(Cond{"is_deleted": false}).OrderBy([]string{"name", "id"}).Before(cursor).Limit(2, 1).FindAllModel(&prev); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		if len(prev) != 2 || prev[0].Id != first[0].Id || prev[1].Id != first[1].Id {
+			t.Errorf("got %+v, want %+v", prev, first)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		cursor, err := sourceCli(ctx).OrderBy([]string{"id"}).Cursor(map[string]any{"id": int64(11)})
+		if err != nil {
+			t.Fatalf("Cursor error: %v", err)
+		}
+
+		if _, err := sourceCli(ctx).After(cursor).FindAll(); err == nil || err.Error() != fmt.Sprintf(MustBeCalledError, "After", "OrderBy") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).OrderBy("id").Before(cursor).FindAll(); err == nil || err.Error() != fmt.Sprintf(CursorOrderByError, "Before") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).OrderBy([]string{"id"}).After(cursor).Before(cursor).FindAll(); err == nil || err.Error() != CursorCalledError {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).OrderBy([]string{"-id"}).After(cursor).FindAll(); err == nil || err.Error() != fmt.Sprintf(CursorMismatchError, "id", "-id") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).OrderBy([]string{"id"}).After("not a cursor").FindAll(); err == nil || !strings.HasPrefix(err.Error(), "cursor is invalid") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).OrderBy([]string{"id"}).Cursor(1); err == nil || err.Error() != fmt.Sprintf(CursorRowTypeError, 1) {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).OrderBy([]string{"name", "id"}).Cursor(map[string]any{"id": 1}); err == nil || err.Error() != fmt.Sprintf(CursorColumnError, "name") {
+			t.Errorf("got error %v", err)
+		}
+		for _, err := range sourceCli(ctx).OrderBy([]string{"id"}).Before(cursor).Rows() {
+			if err == nil || err.Error() != fmt.Sprintf(UnsupportedControllerError, "Before", "Rows") {
+				t.Errorf("got error %v", err)
+			}
+		}
+	})
+}
+
+func TestSqliteChunk(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	t.Run("Chunk", func(t *testing.T) {
+		var (
+			sizes []int
+			ids   []any
+		)
+		err := sourceCli(ctx).WithDeleted().Chunk(4, func(rows []map[string]any) error {
+			sizes = append(sizes, len(rows))
+			for _, row := range rows {
+				ids = append(ids, row["id"])
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Chunk error: %v", err)
+		}
+		if want := []int{4, 4, 4, 3}; !reflect.DeepEqual(sizes, want) {
+			t.Errorf("got sizes %v, want %v", sizes, want)
+		}
+		if len(ids) != 15 || ids[0] != int64(11) || ids[14] != int64(53) {
+			t.Errorf("got ids %v", ids)
+		}
+	})
+
+	t.Run("Chunk updating rows", func(t *testing.T) {
+		var ids []any
+		err := sourceCli(ctx).WithDeleted().Filter(Cond{"type": 1}).Chunk(2, func(rows []map[string]any) error {
+			for _, row := range rows {
+				ids = append(ids, row["id"])
+				if _, err := sourceCli(ctx).WithDeleted().Filter(Cond{"id": row["id"]}).Update(map[string]any{"type": 4}); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("Chunk error: %v", err)
+		}
+		if want := []any{int64(11), int64(21), int64(31), int64(41), int64(51)}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got %v, want %v", ids, want)
+		}
+		if _, err = sourceCli(ctx).WithDeleted().Filter(Cond{"type": 4}).Update(map[string]any{"type": 1}); err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+	})
+
+	t.Run("Chunk stops on error", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := sourceCli(ctx).Chunk(4, func(rows []map[string]any) error {
+			if calls++; calls == 2 {
+				return stop
+			}
+			return nil
+		})
+		if !errors.Is(err, stop) || calls != 2 {
+			t.Errorf("got %d calls, error %v", calls, err)
+		}
+	})
+
+	t.Run("ChunkModel", func(t *testing.T) {
+		var (
+			sources []test.Source
+			ids     []int64
+		)
+		err := sourceCli(ctx).WithDeleted().Where("name = ? OR name = ?", "Acfun", "Apple").ChunkModel(2, &sources, func() error {
+			for _, source := range sources {
+				ids = append(ids, source.Id)
+			}
+			return nil
+		})
+		if err != nil {
+			t.Fatalf("ChunkModel error: %v", err)
+		}
+		if want := []int64{11, 12, 13, 31, 32, 33}; !reflect.DeepEqual(ids, want) {
+			t.Errorf("got %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		noop := func([]map[string]any) error { return nil }
+		if err := sourceCli(ctx).Chunk(0, noop); err == nil || err.Error() != fmt.Sprintf(ChunkSizeError, "Chunk") {
+			t.Errorf("got error %v", err)
+		}
+		if err := sourceCli(ctx).OrderBy([]string{"id"}).Chunk(10, noop); err == nil || err.Error() != fmt.Sprintf(UnsupportedControllerError, "OrderBy", "Chunk") {
+			t.Errorf("got error %v", err)
+		}
+		var sources []test.Source
+		if err := sourceCli(ctx).ChunkModel(10, sources, func() error { return nil }); err == nil || err.Error() != ModelTypeNotSliceError {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestLock(t *testing.T) {
+	ctx := context.Background()
+	mysqlCli := NewController(go_zero.NewOperator(nil), test.Source{})
+	sqliteCli := NewController(sqlite_op.NewOperator(nil), test.Source{})
+
+	t.Run("Lock SQL", func(t *testing.T) {
+		tests := []struct {
+			name string
+			ctl  Controller
+			want string
+		}{
+			{"for update", mysqlCli(ctx).ForUpdate(), " FOR UPDATE"},
+			{"for share", mysqlCli(ctx).ForShare(), " FOR SHARE"},
+			{"skip locked", mysqlCli(ctx).ForUpdate().SkipLocked(), " FOR UPDATE SKIP LOCKED"},
+			{"nowait", mysqlCli(ctx).ForShare().NoWait(), " FOR SHARE NOWAIT"},
+			{"reset", mysqlCli(ctx).ForUpdate().NoWait().Reset(), ""},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				m := tt.ctl.(*Impl)
+				if err := m.haveError(); err != nil {
+					t.Fatalf("got error %v", err)
+				}
+				if got := m.lockSQL(); got != tt.want {
+					t.Errorf("got %q, want %q", got, tt.want)
+				}
+			})
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		tests := []struct {
+			name string
+			ctl  Controller
+			err  string
+		}{
+			{"unsupported", sqliteCli(ctx).ForUpdate(), fmt.Sprintf(OperatorNotSupportedError, "ForUpdate")},
+			{"twice", mysqlCli(ctx).ForUpdate().ForShare(), LockCalledError},
+			{"option first", mysqlCli(ctx).SkipLocked(), fmt.Sprintf(MustBeCalledError, "SkipLocked", "ForUpdate or ForShare")},
+			{"options", mysqlCli(ctx).ForUpdate().SkipLocked().NoWait(), LockOptionCalledError},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				if _, err := tt.ctl.FindAll(); err == nil || err.Error() != tt.err {
+					t.Errorf("got error %v, want %s", err, tt.err)
+				}
+			})
+		}
+	})
+}
+
+func TestSqliteUpsert(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	t.Run("Upsert insert", func(t *testing.T) {
+		num, err := sourceCli(ctx).Upsert(map[string]any{"id": 100, "name": "Upsert", "type": 1, "description": "new"}, nil)
+		if err != nil {
+			t.Fatalf("Upsert error: %v", err)
+		}
+		if num != 1 {
+			t.Errorf("got %d, want 1", num)
+		}
+	})
+
+	t.Run("Upsert update columns", func(t *testing.T) {
+		_, err := sourceCli(ctx).Upsert(map[string]any{"id": 11, "name": "AcfunNew", "type": 1, "description": "changed"}, []string{"name"})
+		if err != nil {
+			t.Fatalf("Upsert error: %v", err)
+		}
+		res, err := sourceCli(ctx).Filter(Cond{"id": 11}).FindOne()
+		if err != nil {
+			t.Fatalf("FindOne error: %v", err)
+		}
+		if res["name"] != "AcfunNew" || res["description"] != "A 站" {
+			t.Errorf("got %v", res)
+		}
+	})
+
+	t.Run("Upsert bulk", func(t *testing.T) {
+		num, err := sourceCli(ctx).Upsert([]test.Source{
+			{Id: 12, Name: "Acfun2", Type: 2, Description: "bulk"},
+			{Id: 101, Name: "Bulk", Type: 1, Description: "bulk"},
+		}, []string{"name", "description"})
+		if err != nil {
+			t.Fatalf("Upsert error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got %d, want 2", num)
+		}
+		got, err := PluckAs[string](sourceCli(ctx).Filter(Cond{"id__in": []int{12, 101}}).OrderBy([]string{"id"}), "name")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []string{"Acfun2", "Bulk"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Upsert bulk column named values", func(t *testing.T) {
+		if _, err := db.Exec(`CREATE TABLE upsert_log (id integer NOT NULL PRIMARY KEY, values_log text NOT NULL)`); err != nil {
+			t.Fatalf("create table error: %v", err)
+		}
+		type upsertLog struct {
+			Id        int64  `db:"id"`
+			ValuesLog string `db:"values_log"`
+		}
+		logCli := NewController(sqlite_op.NewOperator(db, sqlite_op.WithTableName("upsert_log")), upsertLog{})
+
+		for _, log := range []string{"a", "b"} {
+			if _, err := logCli(ctx).Upsert([]upsertLog{{Id: 1, ValuesLog: log + "1"}, {Id: 2, ValuesLog: log + "2"}}, nil); err != nil {
+				t.Fatalf("Upsert error: %v", err)
+			}
+		}
+		got, err := PluckAs[string](logCli(ctx).OrderBy([]string{"id"}), "values_log")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []string{"b1", "b2"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Upsert default columns", func(t *testing.T) {
+		_, err := sourceCli(ctx).Upsert(map[string]any{"id": 13, "name": "Acfun3", "description": "all"}, nil, "id")
+		if err != nil {
+			t.Fatalf("Upsert error: %v", err)
+		}
+		res, err := sourceCli(ctx).Filter(Cond{"id": 13}).FindOne()
+		if err != nil {
+			t.Fatalf("FindOne error: %v", err)
+		}
+		if res["name"] != "Acfun3" || res["description"] != "all" || res["type"] != int64(3) {
+			t.Errorf("got %v", res)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := sourceCli(ctx).Upsert(map[string]any{}, nil); err == nil || err.Error() != "upsert "+DataEmptyError {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Upsert(map[string]any{"id": 1}, nil); err == nil || err.Error() != UpsertColumnsError {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Upsert(map[string]any{"id": 1}, []string{"age"}); err == nil || err.Error() != fmt.Sprintf(UpdateColumnNotExistError, "age") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Upsert(map[string]any{"id": 1}, nil, "uid"); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, "uid") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Filter(Cond{"id": 1}).Upsert(map[string]any{"id": 1}, nil); err == nil || err.Error() != fmt.Sprintf(UnsupportedControllerError, "Filter", "Upsert") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Upsert(1, nil); err == nil || err.Error() != fmt.Sprintf(CreateDataTypeError, reflect.Int) {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestSqliteCreateWith(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	t.Run("CreateWith", func(t *testing.T) {
+		num, err := sourceCli(ctx).CreateWith(map[string]any{"id": 200, "name": "Plain", "description": "plain"})
+		if err != nil {
+			t.Fatalf("CreateWith error: %v", err)
+		}
+		if num != 1 {
+			t.Errorf("got %d, want 1", num)
+		}
+		if _, err = sourceCli(ctx).CreateWith(map[string]any{"id": 200, "name": "Plain", "description": "plain"}); !errors.Is(err, ErrDuplicateKey) {
+			t.Errorf("got error %v, want %v", err, ErrDuplicateKey)
+		}
+	})
+
+	t.Run("IgnoreDuplicates", func(t *testing.T) {
+		num, err := sourceCli(ctx).CreateWith([]map[string]any{
+			{"id": 11, "name": "Ignored", "description": "dup"},
+			{"id": 201, "name": "Inserted", "description": "new"},
+			{"id": 202, "name": "Inserted", "description": "new"},
+		}, IgnoreDuplicates())
+		if err != nil {
+			t.Fatalf("CreateWith error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got %d, want 2", num)
+		}
+		if res, err := sourceCli(ctx).Filter(Cond{"id": 11}).FindOne(); err != nil || res["name"] != "Acfun" {
+			t.Errorf("got %v, %v", res, err)
+		}
+	})
+
+	t.Run("ReplaceDuplicates", func(t *testing.T) {
+		num, err := sourceCli(ctx).CreateWith(test.Source{Id: 12, Name: "Replaced", Type: 9, Description: "replaced"}, ReplaceDuplicates())
+		if err != nil {
+			t.Fatalf("CreateWith error: %v", err)
+		}
+		if num != 1 {
+			t.Errorf("got %d, want 1", num)
+		}
+		res, err := sourceCli(ctx).Filter(Cond{"id": 12}).FindOne()
+		if err != nil {
+			t.Fatalf("FindOne error: %v", err)
+		}
+		if res["name"] != "Replaced" || res["type"] != int64(9) {
+			t.Errorf("got %v", res)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		pgCli := NewController(pg_op.NewOperator(nil), test.Source{})
+		if _, err := pgCli(ctx).CreateWith(map[string]any{"id": 1}, ReplaceDuplicates()); err == nil || err.Error() != fmt.Sprintf(OperatorNotSupportedError, "ReplaceDuplicates") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).CreateWith([]map[string]any{}, IgnoreDuplicates()); err == nil || err.Error() != "create "+DataEmptyError {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).CreateWith(map[string]any{"age": 1}); err == nil || err.Error() != "bulk create "+DataEmptyError {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).Filter(Cond{"id": 1}).CreateWith(map[string]any{"id": 1}); err == nil || err.Error() != fmt.Sprintf(UnsupportedControllerError, "Filter", "CreateWith") {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+func TestSqliteBulkUpdate(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	ctx := context.Background()
+
+	t.Run("BulkUpdate", func(t *testing.T) {
+		num, err := sourceCli(ctx).BatchSize(2).BulkUpdate("id", []map[string]any{
+			{"id": 11, "type": 7, "description": "a"},
+			{"id": 12, "type": 8},
+			{"id": 13, "description": "c"},
+		})
+		if err != nil {
+			t.Fatalf("BulkUpdate error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got %d, want 3", num)
+		}
+
+		var sources []test.Source
+		if err = sourceCli(ctx).Filter(Cond{"name": "Acfun"}).OrderBy([]string{"id"}).FindAllModel(&sources); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		got := [][2]any{}
+		for _, source := range sources {
+			got = append(got, [2]any{source.Type, source.Description})
+		}
+		if want := [][2]any{{int64(7), "a"}, {int64(8), "A 站"}, {int64(3), "c"}}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("BulkUpdate with filter", func(t *testing.T) {
+		num, err := sourceCli(ctx).Filter(Cond{"is_deleted": false}).BulkUpdate("id", []map[string]any{
+			{"id": 21, "type": F("type").Add(10)},
+			{"id": 31, "type": 20},
+		})
+		if err != nil {
+			t.Fatalf("BulkUpdate error: %v", err)
+		}
+		if num != 1 {
+			t.Errorf("got %d, want 1", num)
+		}
+		got, err := PluckAs[int64](sourceCli(ctx).WithDeleted().Filter(Cond{"id__in": []int{21, 31}}).OrderBy([]string{"id"}), "type")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []int64{11, 1}; !reflect.DeepEqual(got, want) {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		if _, err := sourceCli(ctx).BulkUpdate("id", nil); err == nil || err.Error() != "bulk update "+DataEmptyError {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).BulkUpdate("uid", []map[string]any{{"uid": 1}}); err == nil || err.Error() != fmt.Sprintf(ColumnNotExistError, "uid") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).BulkUpdate("id", []map[string]any{{"id": 1, "type": 1}, {"type": 2}}); err == nil || err.Error() != fmt.Sprintf(BulkUpdateKeyError, 1, "id") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).BulkUpdate("id", []map[string]any{{"id": 1, "age": 1}}); err == nil || err.Error() != fmt.Sprintf(UpdateColumnNotExistError, "age") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).BulkUpdate("id", []map[string]any{{"id": 1}}); err == nil || err.Error() != "bulk update "+DataEmptyError {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := sourceCli(ctx).BatchSize(0).BulkUpdate("id", []map[string]any{{"id": 1, "type": 1}}); err == nil || err.Error() != BatchSizeError {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+type softSource struct {
+	Id        int64  `db:"id"`
+	Name      string `db:"name"`
+	Type      int64  `db:"type"`
+	IsDeleted bool   `db:"is_deleted,softDelete"`
+}
+
+func (softSource) TableName() string { return "source" }
+
+type timeSoftSource struct {
+	Id        int64        `db:"id"`
+	Name      string       `db:"name"`
+	DeletedAt sql.NullTime `db:"deleted_at,softDelete"`
+}
+
+func (timeSoftSource) TableName() string { return "source" }
+
+type plainSource struct {
+	Id   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func (plainSource) TableName() string { return "source" }
+
+func TestSqliteSoftDelete(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	softCli := NewController(sqlite_op.NewOperator(db), softSource{})
+	ctx := context.Background()
+
+	count := func(t *testing.T, ctl Controller, want int64) {
+		t.Helper()
+		num, err := ctl.Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != want {
+			t.Errorf("got %d, want %d", num, want)
+		}
+	}
+
+	t.Run("scoped reads", func(t *testing.T) {
+		count(t, softCli(ctx), 9)
+		count(t, softCli(ctx).WithDeleted(), 15)
+		count(t, softCli(ctx).OnlyDeleted(), 6)
+		count(t, softCli(ctx).Where("name = ?", "Apple").OrWhere("name = ?", "Acfun"), 3)
+
+		exist, err := softCli(ctx).Filter(Cond{"id": 31}).Exist()
+		if err != nil {
+			t.Fatalf("Exist error: %v", err)
+		}
+		if exist {
+			t.Errorf("deleted record 31 should not exist")
+		}
+
+		names, err := PluckAs[string](softCli(ctx).Distinct().OrderBy([]string{"name"}), "name")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []string{"Acfun", "Bilibili", "Microsoft"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("got %v, want %v", names, want)
+		}
+
+		var sources []softSource
+		if err = softCli(ctx).OnlyDeleted().Filter(Cond{"type": 1}).OrderBy([]string{"id"}).FindAllModel(&sources); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		if len(sources) != 2 || sources[0].Id != 31 || sources[1].Id != 41 || !sources[0].IsDeleted {
+			t.Errorf("got %+v", sources)
+		}
+
+		query, args, err := softCli(ctx).Filter(Cond{"type": 1}).QuerySQL()
+		if err != nil {
+			t.Fatalf("QuerySQL error: %v", err)
+		}
+		if !strings.HasSuffix(query, ` AND "is_deleted"=?`) || !reflect.DeepEqual(args, []any{1, false}) {
+			t.Errorf("got %s %v", query, args)
+		}
+
+		// the untagged is_deleted column is scoped as well
+		count(t, sourceCli(ctx), 9)
+		count(t, sourceCli(ctx).WithDeleted(), 15)
+		count(t, sourceCli(ctx).OnlyDeleted(), 6)
+	})
+
+	t.Run("Delete and Restore", func(t *testing.T) {
+		num, err := softCli(ctx).Filter(Cond{"name__in": []string{"Acfun", "Apple"}}).Delete()
+		if err != nil {
+			t.Fatalf("Delete error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got %d, want 3", num)
+		}
+		count(t, softCli(ctx), 6)
+
+		if num, err = softCli(ctx).Filter(Cond{"name__in": []string{"Acfun", "Bilibili"}}).Restore(); err != nil {
+			t.Fatalf("Restore error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got %d, want 3", num)
+		}
+		count(t, softCli(ctx), 9)
+
+		if num, err = sourceCli(ctx).Filter(Cond{"id": 31}).Restore(); err != nil {
+			t.Fatalf("Restore error: %v", err)
+		}
+		if num != 1 {
+			t.Errorf("got %d, want 1", num)
+		}
+		count(t, softCli(ctx), 10)
+	})
+
+	t.Run("Update and Remove", func(t *testing.T) {
+		num, err := sourceCli(ctx).Filter(Cond{"name__in": []string{"Google", "Bilibili"}}).Update(map[string]any{"type": 7})
+		if err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got updated %d, want 3", num)
+		}
+		count(t, sourceCli(ctx).WithDeleted().Filter(Cond{"type": 7}), 3)
+
+		if num, err = sourceCli(ctx).Filter(Cond{"name": "Google"}).Remove(); err != nil {
+			t.Fatalf("Remove error: %v", err)
+		}
+		if num != 0 {
+			t.Errorf("got removed %d, want 0", num)
+		}
+		count(t, sourceCli(ctx).OnlyDeleted().Filter(Cond{"name": "Google"}), 3)
+	})
+
+	t.Run("timestamp column", func(t *testing.T) {
+		if _, err := db.Exec(`ALTER TABLE source ADD COLUMN deleted_at datetime`); err != nil {
+			t.Fatalf("add column error: %v", err)
+		}
+		timeCli := NewController(sqlite_op.NewOperator(db), timeSoftSource{})
+
+		num, err := timeCli(ctx).Filter(Cond{"name": "Microsoft"}).Delete()
+		if err != nil {
+			t.Fatalf("Delete error: %v", err)
+		}
+		if num != 3 {
+			t.Errorf("got %d, want 3", num)
+		}
+		count(t, timeCli(ctx), 12)
+
+		var deleted timeSoftSource
+		if err = timeCli(ctx).OnlyDeleted().Filter(Cond{"id": 51}).FindOneModel(&deleted); err != nil {
+			t.Fatalf("FindOneModel error: %v", err)
+		}
+		if !deleted.DeletedAt.Valid || time.Since(deleted.DeletedAt.Time) > time.Minute {
+			t.Errorf("got %+v", deleted)
+		}
+
+		if num, err = timeCli(ctx).Filter(Cond{"id": 51}).Restore(); err != nil {
+			t.Fatalf("Restore error: %v", err)
+		}
+		if num != 1 {
+			t.Errorf("got %d, want 1", num)
+		}
+		count(t, timeCli(ctx), 13)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		plainCli := NewController(sqlite_op.NewOperator(db), plainSource{})
+		if _, err := plainCli(ctx).Filter(Cond{"id": 11}).Delete(); err == nil || err.Error() != fmt.Sprintf(SoftDeleteColumnError, "Delete") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := plainCli(ctx).Restore(); err == nil || err.Error() != fmt.Sprintf(SoftDeleteColumnError, "Restore") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := plainCli(ctx).OnlyDeleted().Count(); err == nil || err.Error() != fmt.Sprintf(SoftDeleteColumnError, "OnlyDeleted") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := softCli(ctx).WithDeleted().OnlyDeleted().Count(); err == nil || err.Error() != DeletedScopeCalledError {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+type stampedSource struct {
+	Id          int64     `db:"id"`
+	Name        string    `db:"name"`
+	Description string    `db:"description"`
+	CreateTime  time.Time `db:"create_time,autoCreateTime"`
+	UpdateTime  time.Time `db:"update_time,autoUpdateTime"`
+}
+
+func (stampedSource) TableName() string { return "source" }
+
+func TestSqliteTimestamps(t *testing.T) {
+	db := newSqliteDB(t)
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sourceCli := NewController(sqlite_op.NewOperator(db), stampedSource{}, WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	times := func(t *testing.T, id int64) [2]time.Time {
+		t.Helper()
+		var source stampedSource
+		if err := sourceCli(ctx).Filter(Cond{"id": id}).FindOneModel(&source); err != nil {
+			t.Fatalf("FindOneModel error: %v", err)
+		}
+		return [2]time.Time{source.CreateTime.UTC(), source.UpdateTime.UTC()}
+	}
+	created := now
+
+	t.Run("Create", func(t *testing.T) {
+		if _, err := sourceCli(ctx).Create(stampedSource{Id: 61, Name: "Yahoo", Description: "雅虎"}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if got, want := times(t, 61), [2]time.Time{now, now}; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		past := now.Add(-time.Hour)
+		if _, err := sourceCli(ctx).Create(map[string]any{"id": 62, "name": "Yahoo", "description": "", "create_time": past}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if got, want := times(t, 62), [2]time.Time{past, now}; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		if _, err := sourceCli(ctx).CreateWith([]map[string]any{
+			{"id": 63, "name": "Yahoo", "description": ""},
+			{"id": 64, "name": "Yahoo", "description": ""},
+		}); err != nil {
+			t.Fatalf("CreateWith error: %v", err)
+		}
+		if got, want := times(t, 64), [2]time.Time{now, now}; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		data := map[string]any{"name": "Yahoo", "description": "雅虎"}
+		res, err := sourceCli(ctx).GetOrCreate(data)
+		if err != nil {
+			t.Fatalf("GetOrCreate error: %v", err)
+		}
+		if len(data) != 2 || res["id"] != int64(61) {
+			t.Errorf("got %v, data %v", res, data)
+		}
+	})
+
+	now = now.Add(time.Hour)
+
+	t.Run("Update", func(t *testing.T) {
+		if _, err := sourceCli(ctx).Filter(Cond{"id": 61}).Update(map[string]any{"description": "Yahoo!"}); err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+		if got, want := times(t, 61), [2]time.Time{created, now}; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		if _, err := sourceCli(ctx).BulkUpdate("id", []map[string]any{{"id": 63, "description": "c"}, {"id": 64, "description": "d"}}); err != nil {
+			t.Fatalf("BulkUpdate error: %v", err)
+		}
+		if got, want := times(t, 64), [2]time.Time{created, now}; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+	})
+
+	now = now.Add(time.Hour)
+
+	t.Run("Upsert", func(t *testing.T) {
+		if _, err := sourceCli(ctx).Upsert(stampedSource{Id: 62, Name: "Yahoo", Description: "upserted"}, nil); err != nil {
+			t.Fatalf("Upsert error: %v", err)
+		}
+		if got, want := times(t, 62), [2]time.Time{created.Add(-time.Hour), now}; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+
+		// the update_time column is added to a copy, the backing array of the caller is not written
+		updateColumns := make([]string, 1, 2)
+		updateColumns[0] = "description"
+		if _, err := sourceCli(ctx).Upsert(map[string]any{"id": 63, "name": "Yahoo", "description": "upserted"}, updateColumns); err != nil {
+			t.Fatalf("Upsert error: %v", err)
+		}
+		if got, want := times(t, 63), [2]time.Time{created, now}; got != want {
+			t.Errorf("got %v, want %v", got, want)
+		}
+		if spare := updateColumns[:2][1]; spare != "" {
+			t.Errorf("got %q written to the update columns", spare)
+		}
+	})
+}
+
+type versionedSource struct {
+	Id      int64  `db:"id"`
+	Name    string `db:"name"`
+	Version int64  `db:"version,version"`
+}
+
+func (versionedSource) TableName() string { return "versioned_source" }
+
+type chVersionedSource struct {
+	Id      int64  `ch:"id"`
+	Name    string `ch:"name"`
+	Version int64  `ch:"version,version"`
+}
+
+func (chVersionedSource) TableName() string { return "versioned_source" }
+
+// The version can not be checked by the ClickHouse operators, they return 0 affected rows,
+// so the error is returned before the mutation is sent.
+func TestClickhouseVersion(t *testing.T) {
+	sourceCli := NewController(clickhouse_go.NewOperator(nil), chVersionedSource{})
+	ctx := context.Background()
+
+	want := fmt.Sprintf(VersionNotSupportedError, "Update")
+	if _, err := sourceCli(ctx).Filter(Cond{"id": 1}).Update(map[string]any{"name": "a", "version": 1}); err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+	if _, err := sourceCli(ctx).UpdateByPK(1, map[string]any{"name": "a", "version": 1}); err == nil || err.Error() != want {
+		t.Errorf("got error %v, want %s", err, want)
+	}
+}
+
+func TestOptimisticLock(t *testing.T) {
+	const ddl = "CREATE TABLE versioned_source (id bigint NOT NULL, name varchar(255) NOT NULL DEFAULT '', version bigint NOT NULL DEFAULT 0, PRIMARY KEY (id))"
+
+	goZeroConn := go_zero.NewMysql(getMysqlAddress())
+	sqlxDB, err := sqlx_op.NewMysql(getMysqlAddress())
+	if err != nil {
+		t.Fatalf("NewMysql error: %v", err)
+	}
+	t.Cleanup(func() { _ = sqlxDB.Close() })
+	sqliteDB := newSqliteDB(t)
+
+	tests := []struct {
+		name string
+		op   Operator
+		exec func(query string) error
+	}{
+		{"go-zero", go_zero.NewOperator(goZeroConn), func(query string) error { _, err := goZeroConn.Exec(query); return err }},
+		{"sqlx mysql", sqlx_op.NewOperator(sqlxDB), func(query string) error { _, err := sqlxDB.Exec(query); return err }},
+		{"sqlite", sqlite_op.NewOperator(sqliteDB), func(query string) error { _, err := sqliteDB.Exec(query); return err }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.exec(ddl); err != nil {
+				t.Fatalf("create table error: %v", err)
+			}
+			t.Cleanup(func() { _ = tt.exec("DROP TABLE versioned_source") })
+
+			sourceCli := NewController(tt.op, versionedSource{})
+			ctx := context.Background()
+
+			if _, err := sourceCli(ctx).Create(versionedSource{Id: 1, Name: "a", Version: 1}); err != nil {
+				t.Fatalf("Create error: %v", err)
+			}
+
+			version := func(t *testing.T) versionedSource {
+				t.Helper()
+				var got versionedSource
+				if err := sourceCli(ctx).Filter(Cond{"id": 1}).FindOneModel(&got); err != nil {
+					t.Fatalf("FindOneModel error: %v", err)
+				}
+				return got
+			}
+
+			num, err := sourceCli(ctx).Filter(Cond{"id": 1}).Update(map[string]any{"name": "b", "version": 1})
+			if err != nil {
+				t.Fatalf("Update error: %v", err)
+			}
+			if got, want := version(t), (versionedSource{Id: 1, Name: "b", Version: 2}); num != 1 || got != want {
+				t.Errorf("got %d %+v, want 1 %+v", num, got, want)
+			}
+
+			if _, err = sourceCli(ctx).Filter(Cond{"id": 1}).Update(map[string]any{"name": "c", "version": 1}); !errors.Is(err, ErrStaleObject) {
+				t.Errorf("got error %v, want %v", err, ErrStaleObject)
+			}
+			if got, want := version(t), (versionedSource{Id: 1, Name: "b", Version: 2}); got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+
+			if _, err = sourceCli(ctx).Filter(Cond{"id": 1}).Update(map[string]any{"name": "d"}); err != nil {
+				t.Fatalf("Update error: %v", err)
+			}
+			if got, want := version(t), (versionedSource{Id: 1, Name: "d", Version: 3}); got != want {
+				t.Errorf("got %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+type account struct {
+	Uid        int64     `db:"uid,pk"`
+	Name       string    `db:"name"`
+	Balance    int64     `db:"balance"`
+	Version    int64     `db:"version,version"`
+	UpdateTime time.Time `db:"update_time,autoUpdateTime"`
+}
+
+type nameOnlySource struct {
+	Name string `db:"name"`
+}
+
+func (nameOnlySource) TableName() string { return "source" }
+
+func TestSqlitePrimaryKey(t *testing.T) {
+	db := newSqliteDB(t)
+	if _, err := db.Exec(`CREATE TABLE account (uid integer PRIMARY KEY AUTOINCREMENT, name varchar(255) NOT NULL DEFAULT '', balance bigint NOT NULL DEFAULT 0, version bigint NOT NULL DEFAULT 0, update_time datetime)`); err != nil {
+		t.Fatalf("create table error: %v", err)
+	}
+	now := time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)
+	sourceCli := NewController(sqlite_op.NewOperator(db), test.Source{})
+	propertyCli := NewController(sqlite_op.NewOperator(db), test.Property{})
+	accountCli := NewController(sqlite_op.NewOperator(db), account{}, WithClock(func() time.Time { return now }))
+	ctx := context.Background()
+
+	t.Run("Get", func(t *testing.T) {
+		res, err := sourceCli(ctx).Get(21)
+		if err != nil {
+			t.Fatalf("Get error: %v", err)
+		}
+		if res["id"] != int64(21) || res["name"] != "Bilibili" {
+			t.Errorf("got %v", res)
+		}
+
+		if res, err = sourceCli(ctx).Get(99); err != nil || len(res) != 0 {
+			t.Errorf("got %v, %v", res, err)
+		}
+	})
+
+	t.Run("UpdateByPK and DeleteByPK", func(t *testing.T) {
+		num, err := sourceCli(ctx).UpdateByPK(21, map[string]any{"type": 9})
+		if err != nil || num != 1 {
+			t.Fatalf("UpdateByPK got %d, %v", num, err)
+		}
+		if num, err = sourceCli(ctx).DeleteByPK(21); err != nil || num != 1 {
+			t.Fatalf("DeleteByPK got %d, %v", num, err)
+		}
+
+		var got test.Source
+		if err = sourceCli(ctx).WithDeleted().Filter(Cond{"id": 21}).FindOneModel(&got); err != nil {
+			t.Fatalf("FindOneModel error: %v", err)
+		}
+		if got.Type != 9 || !got.IsDeleted {
+			t.Errorf("got %+v", got)
+		}
+	})
+
+	t.Run("Create writes the id back", func(t *testing.T) {
+		property := test.Property{SourceId: 11, ColumnName: "author", ShowName: "作者", Description: "作者"}
+		id, err := propertyCli(ctx).Create(&property)
+		if err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if id == 0 || property.Id != id {
+			t.Errorf("got id %d, property %+v", id, property)
+		}
+	})
+
+	t.Run("Save", func(t *testing.T) {
+		a := account{Name: "alice", Balance: 100}
+		num, err := accountCli(ctx).Save(&a)
+		if err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+		if num != 1 || a.Uid == 0 || !a.UpdateTime.Equal(now) {
+			t.Errorf("got %d, %+v", num, a)
+		}
+
+		now = now.Add(time.Hour)
+		stale := a
+		a.Balance = 80
+		if num, err = accountCli(ctx).Save(&a); err != nil {
+			t.Fatalf("Save error: %v", err)
+		}
+		if num != 1 || a.Version != 1 || !a.UpdateTime.Equal(now) {
+			t.Errorf("got %d, %+v", num, a)
+		}
+
+		if num, err = accountCli(ctx).Save(&a); err != nil || num != 0 {
+			t.Errorf("unchanged Save got %d, %v", num, err)
+		}
+
+		stale.Balance = 50
+		if _, err = accountCli(ctx).Save(&stale); !errors.Is(err, ErrStaleObject) {
+			t.Errorf("got error %v, want %v", err, ErrStaleObject)
+		}
+
+		res, err := accountCli(ctx).Get(a.Uid)
+		if err != nil {
+			t.Fatalf("Get error: %v", err)
+		}
+		if res["balance"] != int64(80) || res["version"] != int64(1) {
+			t.Errorf("got %v", res)
+		}
+
+		if _, err = accountCli(ctx).Save(&account{Uid: 99, Name: "bob"}); !errors.Is(err, ErrNotFound) {
+			t.Errorf("got error %v, want %v", err, ErrNotFound)
+		}
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		nameCli := NewController(sqlite_op.NewOperator(db), nameOnlySource{})
+		if _, err := nameCli(ctx).Get("Acfun"); err == nil || err.Error() != fmt.Sprintf(PrimaryKeyError, "Get") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := nameCli(ctx).Save(&nameOnlySource{Name: "a"}); err == nil || err.Error() != fmt.Sprintf(PrimaryKeyError, "Save") {
+			t.Errorf("got error %v", err)
+		}
+		if err := nameCli(ctx).Chunk(10, func([]map[string]any) error { return nil }); err == nil || err.Error() != fmt.Sprintf(PrimaryKeyError, "Chunk") {
+			t.Errorf("got error %v", err)
+		}
+		if _, err := accountCli(ctx).Save(account{Name: "a"}); err == nil || err.Error() != ModelTypeNotStructError {
+			t.Errorf("got error %v", err)
+		}
+	})
+}
+
+var (
+	hookEvents      []string
+	hookPropertyCli func(ctx context.Context) Controller
+)
+
+type hookedSource struct {
+	Id          int64  `db:"id"`
+	Name        string `db:"name"`
+	Type        int64  `db:"type"`
+	Description string `db:"description"`
+	IsDeleted   bool   `db:"is_deleted"`
+}
+
+func (hookedSource) TableName() string { return "source" }
+
+func (s *hookedSource) BeforeCreate(ctx context.Context) error {
+	if s.Name == "" {
+		return errors.New("name is required")
+	}
+	s.Name = strings.ToLower(s.Name)
+	if s.Type == 0 {
+		s.Type = 1
+	}
+	return nil
+}
+
+func (s *hookedSource) AfterCreate(ctx context.Context) error {
+	hookEvents = append(hookEvents, fmt.Sprintf("create %d %s", s.Id, s.Name))
+	_, err := hookPropertyCli(ctx).Create(map[string]any{"source_id": s.Id, "column_name": "title", "description": ""})
+	return err
+}
+
+func (s *hookedSource) BeforeUpdate(ctx context.Context) error {
+	if s.Description == "invalid" {
+		return errors.New("description is invalid")
+	}
+	s.Description = strings.TrimSpace(s.Description)
+	// a new description is reviewed again
+	s.Type = 2
+	return nil
+}
+
+func (s *hookedSource) AfterUpdate(ctx context.Context) error {
+	hookEvents = append(hookEvents, "update "+s.Description)
+	return nil
+}
+
+func (s *hookedSource) AfterFind(ctx context.Context) error {
+	hookEvents = append(hookEvents, fmt.Sprintf("find %d", s.Id))
+	return nil
+}
+
+func (s *hookedSource) BeforeDelete(ctx context.Context) error {
+	if s.Description == "locked" {
+		return errors.New("record is locked")
+	}
+	hookEvents = append(hookEvents, fmt.Sprintf("delete %d %s", s.Id, s.Name))
+	return nil
+}
+
+func (s *hookedSource) AfterDelete(ctx context.Context) error {
+	hookEvents = append(hookEvents, fmt.Sprintf("deleted %d", s.Id))
+	return nil
+}
+
+func TestSqliteHooks(t *testing.T) {
+	db := newSqliteDB(t)
+	sourceCli := NewController(sqlite_op.NewOperator(db), hookedSource{})
+	hookPropertyCli = NewController(sqlite_op.NewOperator(db), test.Property{})
+	ctx := context.Background()
+
+	events := func(t *testing.T, want ...string) {
+		t.Helper()
+		if !reflect.DeepEqual(hookEvents, want) {
+			t.Errorf("got events %q, want %q", hookEvents, want)
+		}
+		hookEvents = nil
+	}
+
+	t.Run("Create", func(t *testing.T) {
+		source := hookedSource{Id: 61, Name: "Yahoo", Description: ""}
+		if _, err := sourceCli(ctx).Create(&source); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if source.Name != "yahoo" {
+			t.Errorf("got %+v", source)
+		}
+		if _, err := sourceCli(ctx).Create(map[string]any{"id": 62, "name": "YAHOO", "description": ""}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		events(t, "create 61 yahoo", "create 62 yahoo")
+
+		names, err := PluckAs[string](sourceCli(ctx).Filter(Cond{"id__in": []int{61, 62}}), "name")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []string{"yahoo", "yahoo"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("got %v, want %v", names, want)
+		}
+		// the type the hook defaults is created for the map as well
+		types, err := PluckAs[int64](sourceCli(ctx).Filter(Cond{"id__in": []int{61, 62}}), "type")
+		if err != nil {
+			t.Fatalf("PluckAs error: %v", err)
+		}
+		if want := []int64{1, 1}; !reflect.DeepEqual(types, want) {
+			t.Errorf("got %v, want %v", types, want)
+		}
+
+		if _, err = sourceCli(ctx).Create(map[string]any{"id": 63, "description": ""}); err == nil || err.Error() != "name is required" {
+			t.Errorf("got error %v", err)
+		}
+		if exist, _ := sourceCli(ctx).Filter(Cond{"id": 63}).Exist(); exist {
+			t.Errorf("record 63 should not be created")
+		}
+		events(t)
+	})
+
+	t.Run("Create in session", func(t *testing.T) {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			t.Fatalf("BeginTx error: %v", err)
+		}
+		if _, err = sourceCli(ctx).WithSession(tx).Create(&hookedSource{Id: 64, Name: "Baidu"}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		if err = tx.Rollback(); err != nil {
+			t.Fatalf("Rollback error: %v", err)
+		}
+		events(t, "create 64 baidu")
+
+		num, err := hookPropertyCli(ctx).Filter(Cond{"source_id": 64}).Count()
+		if err != nil {
+			t.Fatalf("Count error: %v", err)
+		}
+		if num != 0 {
+			t.Errorf("the property of the hook should be rolled back, got %d", num)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		if _, err := sourceCli(ctx).Filter(Cond{"id": 61}).Update(map[string]any{"description": " search "}); err != nil {
+			t.Fatalf("Update error: %v", err)
+		}
+		if _, err := sourceCli(ctx).Filter(Cond{"id": 61}).Update(map[string]any{"description": "invalid"}); err == nil || err.Error() != "description is invalid" {
+			t.Errorf("got error %v", err)
+		}
+		events(t, "update search")
+
+		var source hookedSource
+		if err := sourceCli(ctx).Filter(Cond{"id": 61}).FindOneModel(&source); err != nil {
+			t.Fatalf("FindOneModel error: %v", err)
+		}
+		if source.Description != "search" || source.Type != 2 {
+			t.Errorf("got %+v", source)
+		}
+		var sources []hookedSource
+		if err := sourceCli(ctx).Filter(Cond{"id__in": []int{61, 62}}).OrderBy([]string{"id"}).FindAllModel(&sources); err != nil {
+			t.Fatalf("FindAllModel error: %v", err)
+		}
+		events(t, "find 61", "find 61", "find 62")
+	})
+
+	t.Run("Delete and Remove", func(t *testing.T) {
+		num, err := sourceCli(ctx).Filter(Cond{"id__in": []int{61, 62}}).OrderBy([]string{"id"}).Delete()
+		if err != nil {
+			t.Fatalf("Delete error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got %d, want 2", num)
+		}
+		events(t, "delete 61 yahoo", "delete 62 yahoo", "deleted 61", "deleted 62")
+
+		if num, err = sourceCli(ctx).WithDeleted().Filter(Cond{"id": 62}).Remove(); err != nil || num != 1 {
+			t.Fatalf("Remove got %d, %v", num, err)
+		}
+		events(t, "delete 62 yahoo", "deleted 62")
+
+		if _, err = sourceCli(ctx).Create(map[string]any{"id": 65, "name": "Lock", "description": "locked"}); err != nil {
+			t.Fatalf("Create error: %v", err)
+		}
+		events(t, "create 65 lock")
+		if _, err = sourceCli(ctx).DeleteByPK(65); err == nil || err.Error() != "record is locked" {
+			t.Errorf("got error %v", err)
+		}
+		if _, err = sourceCli(ctx).Filter(Cond{"id": 65}).Remove(); err == nil || err.Error() != "record is locked" {
+			t.Errorf("got error %v", err)
+		}
+		if exist, _ := sourceCli(ctx).Filter(Cond{"id": 65}).Exist(); !exist {
+			t.Errorf("locked record 65 should not be deleted")
+		}
+		events(t)
+	})
+}
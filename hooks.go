@@ -0,0 +1,206 @@
+package norm
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+)
+
+// The hooks are optional interfaces of the model, implemented with a pointer receiver, e.g.
+//
+//	func (u *User) BeforeCreate(ctx context.Context) error {
+//		u.Email = strings.ToLower(u.Email)
+//		return nil
+//	}
+//
+// An error of a Before hook aborts the operation, an error of an After hook is returned after it is done,
+// so it should be used with WithSession to roll the transaction back. The ctx of the hooks carries the session
+// of WithSession, the controllers created with it run in the same transaction.
+type (
+	// BeforeCreateHook is called by Create, CreateWith and Save before the record is inserted.
+	BeforeCreateHook interface {
+		BeforeCreate(ctx context.Context) error
+	}
+	// AfterCreateHook is called by Create, CreateWith and Save after the record is inserted.
+	AfterCreateHook interface {
+		AfterCreate(ctx context.Context) error
+	}
+	// BeforeUpdateHook is called by Update, UpdateByPK and Save before the records are updated.
+	BeforeUpdateHook interface {
+		BeforeUpdate(ctx context.Context) error
+	}
+	// AfterUpdateHook is called by Update, UpdateByPK and Save after the records are updated.
+	AfterUpdateHook interface {
+		AfterUpdate(ctx context.Context) error
+	}
+	// BeforeDeleteHook is called by Delete, DeleteByPK and Remove for each record before the records are deleted.
+	BeforeDeleteHook interface {
+		BeforeDelete(ctx context.Context) error
+	}
+	// AfterDeleteHook is called by Delete, DeleteByPK and Remove for each record after the records are deleted.
+	AfterDeleteHook interface {
+		AfterDelete(ctx context.Context) error
+	}
+	// AfterFindHook is called by FindOneModel and FindAllModel for each record found.
+	AfterFindHook interface {
+		AfterFind(ctx context.Context) error
+	}
+)
+
+// sessionKey is the context key of the session set by WithSession.
+type sessionKey struct{}
+
+// hasHook reports whether the model implements the hook H.
+func hasHook[H any](m *Impl) bool {
+	_, ok := m.modelPtr.(H)
+	return ok
+}
+
+// callHook calls the hook H of the models which implement it, it stops at the first error.
+func callHook[H any](m *Impl, call func(H, context.Context) error, models ...any) error {
+	for _, model := range models {
+		if hook, ok := model.(H); ok {
+			if err := call(hook, m.ctx()); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// newModel returns a pointer to a new model with the fields of the data set, and the columns which are set.
+func (m *Impl) newModel(data map[string]any) (modelPtr any, columns []string) {
+	v := reflect.New(reflect.TypeOf(m.modelPtr).Elem())
+	for column, value := range data {
+		if field := fieldByColumn(v.Elem(), m.operator.GetDBTag(), column); field.IsValid() && setField(field, value) {
+			columns = append(columns, column)
+		}
+	}
+	return v.Interface(), columns
+}
+
+// hookData returns a copy of the data with the values of the model after a hook, they are the columns of the data
+// and the other columns the hook set, which are the ones not zero as the model only had the columns of the data set.
+func (m *Impl) hookData(modelPtr any, columns []string, data map[string]any) map[string]any {
+	data = maps.Clone(data)
+	for column, value := range modelStruct2Map(modelPtr, m.operator.GetDBTag()) {
+		if slices.Contains(columns, column) || !isZeroValue(value) {
+			data[column] = value
+		}
+	}
+	return data
+}
+
+// modelPointers returns pointers to the model structs of the data, a struct value is copied,
+// the elements of a slice are pointed to in place.
+func modelPointers(data any) []any {
+	v := reflect.ValueOf(data)
+	switch {
+	case v.Kind() == reflect.Pointer && v.Elem().Kind() == reflect.Struct:
+		return []any{data}
+	case v.Kind() == reflect.Struct:
+		ptr := reflect.New(v.Type())
+		ptr.Elem().Set(v)
+		return []any{ptr.Interface()}
+	}
+
+	if v.Kind() == reflect.Pointer {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil
+	}
+	models := make([]any, v.Len())
+	for i := range v.Len() {
+		if elem := v.Index(i); elem.Kind() == reflect.Pointer {
+			models[i] = elem.Interface()
+		} else {
+			models[i] = elem.Addr().Interface()
+		}
+	}
+	return models
+}
+
+// beforeCreate calls BeforeCreate of the data of Create and returns the data with its changes,
+// and the models to call AfterCreate with. A map is changed through a model with its fields.
+func (m *Impl) beforeCreate(data any) (any, []any, error) {
+	if !hasHook[BeforeCreateHook](m) && !hasHook[AfterCreateHook](m) {
+		return data, nil, nil
+	}
+
+	switch d := data.(type) {
+	case map[string]any:
+		model, columns := m.newModel(d)
+		if err := callHook(m, BeforeCreateHook.BeforeCreate, model); err != nil {
+			return nil, nil, err
+		}
+		return m.hookData(model, columns, d), []any{model}, nil
+	case []map[string]any:
+		rows := make([]map[string]any, len(d))
+		models := make([]any, len(d))
+		for i, row := range d {
+			model, columns := m.newModel(row)
+			if err := callHook(m, BeforeCreateHook.BeforeCreate, model); err != nil {
+				return nil, nil, err
+			}
+			rows[i], models[i] = m.hookData(model, columns, row), model
+		}
+		return rows, models, nil
+	}
+
+	models := modelPointers(data)
+	if err := callHook(m, BeforeCreateHook.BeforeCreate, models...); err != nil {
+		return nil, nil, err
+	}
+	// a copied struct value is created from its copy, which has the changes of the hook
+	if v := reflect.ValueOf(data); v.Kind() == reflect.Struct {
+		data = models[0]
+	}
+	return data, models, nil
+}
+
+// beforeUpdate calls BeforeUpdate of a model with the fields of the data, and returns the data
+// with its changes and the model to call AfterUpdate with.
+func (m *Impl) beforeUpdate(data map[string]any) (map[string]any, any, error) {
+	if !hasHook[BeforeUpdateHook](m) && !hasHook[AfterUpdateHook](m) {
+		return data, nil, nil
+	}
+
+	model, columns := m.newModel(data)
+	if err := callHook(m, BeforeUpdateHook.BeforeUpdate, model); err != nil {
+		return nil, nil, err
+	}
+	return m.hookData(model, columns, data), model, nil
+}
+
+// deleteHooks calls the delete of fn between BeforeDelete and AfterDelete of each record matching the query set,
+// the records are loaded before fn is called, so the hooks see what is deleted.
+func (m *Impl) deleteHooks(fn func() (int64, error)) (num int64, err error) {
+	if !hasHook[BeforeDeleteHook](m) && !hasHook[AfterDeleteHook](m) {
+		return fn()
+	}
+
+	query, args := m.buildQuery("")
+	modelSlicePtr := reflect.New(reflect.SliceOf(reflect.TypeOf(m.modelPtr).Elem())).Interface()
+	if err = m.operator.FindAll(m.ctx(), modelSlicePtr, query+m.lockSQL(), args...); err != nil {
+		return 0, err
+	}
+	models := modelPointers(modelSlicePtr)
+
+	if err = callHook(m, BeforeDeleteHook.BeforeDelete, models...); err != nil {
+		return 0, err
+	}
+	if num, err = fn(); err != nil {
+		return num, err
+	}
+	return num, callHook(m, AfterDeleteHook.AfterDelete, models...)
+}
+
+// findHook calls AfterFind of the model of modelPtr, or of each model in the slice of modelSlicePtr.
+func (m *Impl) findHook(modelPtr any) error {
+	if !hasHook[AfterFindHook](m) {
+		return nil
+	}
+	return callHook(m, AfterFindHook.AfterFind, modelPointers(modelPtr)...)
+}
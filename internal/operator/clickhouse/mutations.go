@@ -0,0 +1,110 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+)
+
+const (
+	updatePrefix = "UPDATE "
+	deletePrefix = "DELETE FROM "
+	setKeyword   = " SET "
+	whereKeyword = " WHERE "
+	// whereAll is used when the controller sends no condition, ClickHouse requires a WHERE in mutations.
+	whereAll = " WHERE 1"
+)
+
+var ErrMutationQuery = errors.New("unsupported mutation query")
+
+// Exec runs a statement on the ClickHouse connection.
+type Exec func(ctx context.Context, query string, args ...any) error
+
+// Mutations are the options of the Update and Remove mutations shared by the ClickHouse operators.
+type Mutations struct {
+	// Lightweight chooses the lightweight "DELETE FROM ... WHERE" for Remove instead of the ALTER TABLE mutation.
+	Lightweight bool
+	// Sync is the mutations_sync level of the ALTER TABLE mutations.
+	Sync int
+}
+
+// NewMutations returns the default options, lightweight deletes which return at once.
+func NewMutations() Mutations {
+	return Mutations{Lightweight: true}
+}
+
+// Update runs the UPDATE query of the controller as an ALTER TABLE ... UPDATE mutation with exec.
+// ClickHouse does not report affected rows, so the returned number is always 0.
+func (m Mutations) Update(ctx context.Context, exec Exec, query string, args ...any) (int64, error) {
+	mutation, err := UpdateMutation(query, m.Sync)
+	if err != nil {
+		return 0, err
+	}
+	return 0, exec(ctx, mutation, args...)
+}
+
+// Remove runs the DELETE query of the controller as a lightweight delete or an ALTER TABLE ... DELETE mutation with exec.
+// ClickHouse does not report affected rows, so the returned number is always 0.
+func (m Mutations) Remove(ctx context.Context, exec Exec, query string, args ...any) (int64, error) {
+	mutation, err := DeleteMutation(query, m.Lightweight, m.Sync)
+	if err != nil {
+		return 0, err
+	}
+	return 0, exec(ctx, mutation, args...)
+}
+
+// UpdateMutation turns "UPDATE t SET a=? WHERE ..." into "ALTER TABLE t UPDATE a=? WHERE ...".
+// A positive sync is sent as the mutations_sync setting to wait for the mutation to finish.
+func UpdateMutation(query string, sync int) (string, error) {
+	if !strings.HasPrefix(query, updatePrefix) {
+		return "", ErrMutationQuery
+	}
+	pos := strings.Index(query, setKeyword)
+	if pos < 0 {
+		return "", ErrMutationQuery
+	}
+
+	table := query[len(updatePrefix):pos]
+	assignments, condition := splitWhere(query[pos+len(setKeyword):])
+
+	return "ALTER TABLE " + table + " UPDATE " + assignments + whereOrAll(condition) + syncSettings(sync), nil
+}
+
+// DeleteMutation turns "DELETE FROM t WHERE ..." into a lightweight delete,
+// or into "ALTER TABLE t DELETE WHERE ..." when lightweight is false.
+// The sync level only applies to ALTER TABLE mutations.
+func DeleteMutation(query string, lightweight bool, sync int) (string, error) {
+	if !strings.HasPrefix(query, deletePrefix) {
+		return "", ErrMutationQuery
+	}
+
+	table, condition := splitWhere(query[len(deletePrefix):])
+
+	if lightweight {
+		return deletePrefix + table + whereOrAll(condition), nil
+	}
+	return "ALTER TABLE " + table + " DELETE" + whereOrAll(condition) + syncSettings(sync), nil
+}
+
+func splitWhere(query string) (head, condition string) {
+	pos := strings.Index(query, whereKeyword)
+	if pos < 0 {
+		return query, ""
+	}
+	return query[:pos], query[pos:]
+}
+
+func whereOrAll(condition string) string {
+	if condition == "" {
+		return whereAll
+	}
+	return condition
+}
+
+func syncSettings(sync int) string {
+	if sync <= 0 {
+		return ""
+	}
+	return " SETTINGS mutations_sync = " + strconv.Itoa(sync)
+}
@@ -0,0 +1,141 @@
+package clickhouse
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestUpdateMutation(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		sync  int
+		want  string
+		err   error
+	}{
+		{
+			name:  "with condition",
+			query: "UPDATE `source` SET `name`=?,`type`=? WHERE (`id` = ?)",
+			want:  "ALTER TABLE `source` UPDATE `name`=?,`type`=? WHERE (`id` = ?)",
+		},
+		{
+			name:  "without condition",
+			query: "UPDATE `source` SET `name`=?",
+			want:  "ALTER TABLE `source` UPDATE `name`=? WHERE 1",
+		},
+		{
+			name:  "sync",
+			query: "UPDATE `source` SET `name`=? WHERE (`id` = ?)",
+			sync:  2,
+			want:  "ALTER TABLE `source` UPDATE `name`=? WHERE (`id` = ?) SETTINGS mutations_sync = 2",
+		},
+		{
+			name:  "not an update",
+			query: "DELETE FROM `source`",
+			err:   ErrMutationQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UpdateMutation(tt.query, tt.sync)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeleteMutation(t *testing.T) {
+	tests := []struct {
+		name        string
+		query       string
+		lightweight bool
+		sync        int
+		want        string
+		err         error
+	}{
+		{
+			name:        "lightweight",
+			query:       "DELETE FROM `source` WHERE (`id` = ?)",
+			lightweight: true,
+			sync:        2,
+			want:        "DELETE FROM `source` WHERE (`id` = ?)",
+		},
+		{
+			name:        "lightweight without condition",
+			query:       "DELETE FROM `source`",
+			lightweight: true,
+			want:        "DELETE FROM `source` WHERE 1",
+		},
+		{
+			name:  "alter",
+			query: "DELETE FROM `source` WHERE (`id` = ?)",
+			sync:  1,
+			want:  "ALTER TABLE `source` DELETE WHERE (`id` = ?) SETTINGS mutations_sync = 1",
+		},
+		{
+			name:  "not a delete",
+			query: "UPDATE `source` SET `name`=?",
+			err:   ErrMutationQuery,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DeleteMutation(tt.query, tt.lightweight, tt.sync)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("got error %v, want %v", err, tt.err)
+			}
+			if got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMutations(t *testing.T) {
+	var got []string
+	var gotArgs []any
+	exec := func(ctx context.Context, query string, args ...any) error {
+		got, gotArgs = append(got, query), args
+		return nil
+	}
+	ctx := context.Background()
+
+	m := NewMutations()
+	if num, err := m.Update(ctx, exec, "UPDATE `source` SET `name`=? WHERE (`id` = ?)", "a", 1); err != nil || num != 0 {
+		t.Fatalf("got %d, %v, want 0, nil", num, err)
+	}
+	if !reflect.DeepEqual(gotArgs, []any{"a", 1}) {
+		t.Errorf("got args %v", gotArgs)
+	}
+	if _, err := m.Remove(ctx, exec, "DELETE FROM `source` WHERE (`id` = ?)", 1); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+
+	m.Lightweight, m.Sync = false, 2
+	if _, err := m.Remove(ctx, exec, "DELETE FROM `source` WHERE (`id` = ?)", 1); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+
+	want := []string{
+		"ALTER TABLE `source` UPDATE `name`=? WHERE (`id` = ?)",
+		"DELETE FROM `source` WHERE (`id` = ?)",
+		"ALTER TABLE `source` DELETE WHERE (`id` = ?) SETTINGS mutations_sync = 2",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+
+	if _, err := m.Update(ctx, exec, "DELETE FROM `source`"); !errors.Is(err, ErrMutationQuery) {
+		t.Errorf("got error %v, want %v", err, ErrMutationQuery)
+	}
+	execErr := errors.New("exec error")
+	if _, err := m.Remove(ctx, func(context.Context, string, ...any) error { return execErr }, "DELETE FROM `source`"); !errors.Is(err, execErr) {
+		t.Errorf("got error %v, want %v", err, execErr)
+	}
+}
@@ -12,6 +12,7 @@ var Operators = map[string]string{
 	"is_null": "`%s` IS NULL",
 
 	"in":          "`%s`%s in",
+	"exists":      "%s EXISTS",
 	"between":     "`%s`%s BETWEEN ? AND ?",
 	"contains":    "`%s`%s like ?",
 	"icontains":   "`%s`%s ilike ?",
@@ -2,6 +2,9 @@ package operator
 
 import (
 	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 )
 
 var (
@@ -11,12 +14,28 @@ var (
 
 const (
 	placeholder = "?"
+	quote       = "`"
+)
+
+// The row locks of LockSQL, an operator returns an empty clause for the locks its database does not support.
+const (
+	LockForUpdate  = "for_update"
+	LockForShare   = "for_share"
+	LockSkipLocked = "skip_locked"
+	LockNoWait     = "nowait"
+)
+
+// The insert modes of InsertSQL, an operator returns an empty template for the modes its database does not support.
+const (
+	InsertIgnore  = "ignore"
+	InsertReplace = "replace"
 )
 
 type AddOptions struct {
 	TableName   string
 	Placeholder string
 	DBTag       string
+	Quote       string
 }
 
 type AddFunc func(params *AddOptions)
@@ -25,6 +44,7 @@ func DefaultAddOptions(dbTag string) AddOptions {
 	return AddOptions{
 		Placeholder: placeholder,
 		DBTag:       dbTag,
+		Quote:       quote,
 	}
 }
 
@@ -45,3 +65,139 @@ func WithDBTag(dbTag string) AddFunc {
 		op.DBTag = dbTag
 	}
 }
+
+func WithQuote(quote string) AddFunc {
+	return func(op *AddOptions) {
+		op.Quote = quote
+	}
+}
+
+// QuoteIdent wraps an identifier with the given quote character.
+// An identifier already wrapped with backticks or double quotes is unwrapped first,
+// so names produced for one dialect can be re-quoted for another.
+// A qualified identifier like table.column is quoted part by part,
+// a quote character left inside a part is escaped by doubling it.
+func QuoteIdent(quote, ident string) string {
+	if ident == "" {
+		return ident
+	}
+	parts := strings.Split(ident, ".")
+	for i, part := range parts {
+		part = strings.Trim(part, "`\"")
+		if quote != "" {
+			part = strings.ReplaceAll(part, quote, quote+quote)
+		}
+		parts[i] = quote + part + quote
+	}
+	return strings.Join(parts, ".")
+}
+
+// Rebind converts the `?` bind markers generated by norm into the given placeholder style.
+// A `?` placeholder keeps the query as it is, any other placeholder is numbered from 1,
+// e.g. `$` gives `$1, $2, ...` for PostgreSQL.
+// Markers inside quoted strings or identifiers are left untouched.
+func Rebind(placeholder, query string) string {
+	if placeholder == "" || placeholder == "?" || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var (
+		b     strings.Builder
+		n     int
+		quote rune
+	)
+	b.Grow(len(query) + 10)
+
+	for _, r := range query {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '\'' || r == '"' || r == '`':
+			quote = r
+		case r == '?':
+			n++
+			b.WriteString(placeholder)
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// Rebind converts the `?` bind markers of the query into the placeholder of the options,
+// every operator passes its queries through it before they reach the driver.
+func (o AddOptions) Rebind(query string) string {
+	return Rebind(o.Placeholder, query)
+}
+
+// OnDuplicateKeyUpdate returns the MySQL upsert clause which updates the quoted columns with the inserted values,
+// e.g. " ON DUPLICATE KEY UPDATE `a`=VALUES(`a`)".
+func OnDuplicateKeyUpdate(updateColumns []string) string {
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = column + "=VALUES(" + column + ")"
+	}
+	return " ON DUPLICATE KEY UPDATE " + strings.Join(sets, ",")
+}
+
+// OnConflictUpdate returns the PostgreSQL and SQLite upsert clause which updates the quoted columns with the inserted
+// values when the quoted conflict columns are duplicated, e.g. ` ON CONFLICT ("id") DO UPDATE SET "a"=EXCLUDED."a"`.
+func OnConflictUpdate(conflictColumns, updateColumns []string) string {
+	sets := make([]string, len(updateColumns))
+	for i, column := range updateColumns {
+		sets[i] = column + "=EXCLUDED." + column
+	}
+	return " ON CONFLICT (" + strings.Join(conflictColumns, ",") + ") DO UPDATE SET " + strings.Join(sets, ",")
+}
+
+// BuildBulkInsertQuery expands the single row VALUES clause of an insert query to the given rows.
+func BuildBulkInsertQuery(query string, rows int) (string, error) {
+	if rows <= 1 {
+		return query, nil
+	}
+
+	lower := strings.ToLower(query)
+
+	// Limit VALUES keyword search to the main INSERT ... VALUES clause.
+	// If the query contains an "ON DUPLICATE KEY" or "ON CONFLICT" section, ignore everything after it
+	// so we don't accidentally match the MySQL VALUES() function or a column like `values_log` there.
+	searchLower := lower
+	for _, clause := range []string{" on duplicate key", " on conflict"} {
+		if searchEnd := strings.Index(searchLower, clause); searchEnd > 0 {
+			searchLower = searchLower[:searchEnd]
+		}
+	}
+
+	// Use the last occurrence so identifiers like `values_log` or `default_values`
+	// in table/column names don't confuse us.
+	pos := strings.LastIndex(searchLower, "values")
+	if pos < 0 {
+		return "", fmt.Errorf("invalid insert query, missing VALUES: %q", query)
+	}
+
+	head := strings.TrimSpace(query[:pos+len("values")])
+	tail := strings.TrimSpace(query[pos+len("values"):])
+	left := strings.IndexByte(tail, '(')
+	right := strings.IndexByte(tail, ')')
+	if left < 0 || right <= left {
+		return "", fmt.Errorf("invalid insert query, bad values placeholder: %q", query)
+	}
+
+	rowTemplate := tail[left : right+1]
+	suffix := strings.TrimSpace(tail[right+1:])
+
+	valueTemplates := make([]string, rows)
+	for i := range valueTemplates {
+		valueTemplates[i] = rowTemplate
+	}
+
+	bulkQuery := head + " " + strings.Join(valueTemplates, ",")
+	if suffix != "" {
+		bulkQuery += " " + suffix
+	}
+	return bulkQuery, nil
+}
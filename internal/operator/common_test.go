@@ -0,0 +1,136 @@
+package operator
+
+import "testing"
+
+func TestBuildBulkInsertQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		rows    int
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "zero row keeps original",
+			query: "INSERT INTO `t` (`a`,`b`) VALUES (?,?)",
+			rows:  0,
+			want:  "INSERT INTO `t` (`a`,`b`) VALUES (?,?)",
+		},
+		{
+			name:  "single row keeps original",
+			query: "INSERT INTO `t` (`a`,`b`) VALUES (?,?)",
+			rows:  1,
+			want:  "INSERT INTO `t` (`a`,`b`) VALUES (?,?)",
+		},
+		{
+			name:  "expand multi rows",
+			query: "INSERT INTO `t` (`a`,`b`) VALUES (?,?)",
+			rows:  3,
+			want:  "INSERT INTO `t` (`a`,`b`) VALUES (?,?),(?,?),(?,?)",
+		},
+		{
+			name:  "expand with on conflict column named values",
+			query: `INSERT INTO "t" ("id","values_log") VALUES (?,?) ON CONFLICT ("id") DO UPDATE SET "values_log"=EXCLUDED."values_log"`,
+			rows:  2,
+			want:  `INSERT INTO "t" ("id","values_log") VALUES (?,?),(?,?) ON CONFLICT ("id") DO UPDATE SET "values_log"=EXCLUDED."values_log"`,
+		},
+		{
+			name:  "expand with on duplicate key column named values",
+			query: "INSERT INTO `t` (`id`,`values_log`) VALUES (?,?) ON DUPLICATE KEY UPDATE `values_log`=VALUES(`values_log`)",
+			rows:  2,
+			want:  "INSERT INTO `t` (`id`,`values_log`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `values_log`=VALUES(`values_log`)",
+		},
+		{
+			name:  "expand with suffix",
+			query: "INSERT INTO `t` (`a`,`b`) VALUES (?,?) ON DUPLICATE KEY UPDATE `a`=VALUES(`a`)",
+			rows:  2,
+			want:  "INSERT INTO `t` (`a`,`b`) VALUES (?,?),(?,?) ON DUPLICATE KEY UPDATE `a`=VALUES(`a`)",
+		},
+		{
+			name:    "invalid query",
+			query:   "INSERT INTO `t` (`a`,`b`)",
+			rows:    2,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BuildBulkInsertQuery(tt.query, tt.rows)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("expected error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQuoteIdent(t *testing.T) {
+	tests := []struct {
+		name  string
+		quote string
+		input string
+		want  string
+	}{
+		{"empty_string", "`", "", ""},
+		{"simple", "`", "id", "`id`"},
+		{"already_wrapped_string", "`", "`test`", "`test`"},
+		{"double_quote", `"`, "id", `"id"`},
+		{"requote_backticks", `"`, "`test`", `"test"`},
+		{"requote_double_quotes", "`", `"test"`, "`test`"},
+		{"no_quote", "", "id", "id"},
+		{"qualified", "`", "user.name", "`user`.`name`"},
+		{"qualified_requote", `"`, "`user`.`name`", `"user"."name"`},
+		{"escape_embedded_quote", "`", "x`; DROP TABLE t; --", "`x``; DROP TABLE t; --`"},
+		{"escape_embedded_double_quote", `"`, `x"y`, `"x""y"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := QuoteIdent(tt.quote, tt.input); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUpsertClause(t *testing.T) {
+	if got, want := OnDuplicateKeyUpdate([]string{"`a`", "`b`"}), " ON DUPLICATE KEY UPDATE `a`=VALUES(`a`),`b`=VALUES(`b`)"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := OnConflictUpdate([]string{`"id"`}, []string{`"a"`, `"b"`}), ` ON CONFLICT ("id") DO UPDATE SET "a"=EXCLUDED."a","b"=EXCLUDED."b"`; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestRebind(t *testing.T) {
+	tests := []struct {
+		name        string
+		placeholder string
+		query       string
+		want        string
+	}{
+		{"question_mark_keeps_query", "?", "SELECT * FROM `t` WHERE `a` = ? AND `b` = ?", "SELECT * FROM `t` WHERE `a` = ? AND `b` = ?"},
+		{"empty_keeps_query", "", "SELECT * FROM t WHERE a = ?", "SELECT * FROM t WHERE a = ?"},
+		{"dollar", "$", `SELECT * FROM "t" WHERE "a" = ? AND "b" IN (?,?)`, `SELECT * FROM "t" WHERE "a" = $1 AND "b" IN ($2,$3)`},
+		{"named", "@p", "UPDATE t SET a=? WHERE b=?", "UPDATE t SET a=@p1 WHERE b=@p2"},
+		{"skip_quoted", "$", `SELECT '?' AS "?", a FROM t WHERE b = ?`, `SELECT '?' AS "?", a FROM t WHERE b = $1`},
+		{"no_marker", "$", "SELECT count(1) FROM t", "SELECT count(1) FROM t"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Rebind(tt.placeholder, tt.query); got != tt.want {
+				t.Fatalf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
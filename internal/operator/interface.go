@@ -4,17 +4,25 @@ import "context"
 
 type Operator interface {
 	OperatorSQL(operator, method string) string
+	LockSQL(lock string) string
+	InsertSQL(mode string) string
+	UpsertSQL(conflictColumns, updateColumns []string) string
 	GetPlaceholder() string
+	GetQuote() string
 	GetDBTag() string
 	GetTableName() string
 	SetTableName(tableName string) Operator
+	SetPrimaryKey(column string) Operator
+	ReportsAffectedRows() bool
 	WithSession(session any) Operator
 	Insert(ctx context.Context, query string, args ...any) (id int64, err error)
 	BulkInsert(ctx context.Context, query string, args []string, data []map[string]any) (num int64, err error)
 	Remove(ctx context.Context, query string, args ...any) (num int64, err error)
 	Update(ctx context.Context, query string, args ...any) (num int64, err error)
 	Count(ctx context.Context, condition string, args ...any) (num int64, err error)
+	CountDistinct(ctx context.Context, column, condition string, args ...any) (num int64, err error)
 	Exist(ctx context.Context, condition string, args ...any) (bool, error)
 	FindOne(ctx context.Context, model any, query string, args ...any) (err error)
 	FindAll(ctx context.Context, model any, query string, args ...any) (err error)
+	FindEach(ctx context.Context, model any, fn func() error, query string, args ...any) (err error)
 }
@@ -1,5 +1,7 @@
 package mysql
 
+import "github.com/leisurelicht/norm/internal/operator"
+
 var Operators = map[string]string{
 	"exact":   "`%s` = ?",
 	"exclude": "`%s` != ?",
@@ -12,6 +14,7 @@ var Operators = map[string]string{
 	"is_null": "`%s` IS NULL",
 
 	"in":          "`%s`%s IN",
+	"exists":      "%s EXISTS",
 	"between":     "`%s`%s BETWEEN ? AND ?",
 	"contains":    "`%s`%s LIKE BINARY ?",
 	"icontains":   "`%s`%s LIKE ?",
@@ -25,3 +28,17 @@ var Operators = map[string]string{
 }
 
 var Methods = map[string]string{}
+
+// Inserts are the insert templates of the insert modes.
+var Inserts = map[string]string{
+	operator.InsertIgnore:  "INSERT IGNORE INTO %s (%s) VALUES (%s)",
+	operator.InsertReplace: "REPLACE INTO %s (%s) VALUES (%s)",
+}
+
+// Locks are the row locking clauses appended to SELECT.
+var Locks = map[string]string{
+	operator.LockForUpdate:  " FOR UPDATE",
+	operator.LockForShare:   " FOR SHARE",
+	operator.LockSkipLocked: " SKIP LOCKED",
+	operator.LockNoWait:     " NOWAIT",
+}
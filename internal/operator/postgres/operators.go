@@ -0,0 +1,43 @@
+package postgres
+
+import "github.com/leisurelicht/norm/internal/operator"
+
+var Operators = map[string]string{
+	"exact":   `"%s" = ?`,
+	"exclude": `"%s" != ?`,
+	"iexact":  `"%s" ILIKE ?`,
+	"gt":      `"%s" > ?`,
+	"gte":     `"%s" >= ?`,
+	"lt":      `"%s" < ?`,
+	"lte":     `"%s" <= ?`,
+	"len":     `LENGTH("%s") = ?`,
+	"is_null": `"%s" IS NULL`,
+
+	"in":          `"%s"%s IN`,
+	"exists":      `%s EXISTS`,
+	"between":     `"%s"%s BETWEEN ? AND ?`,
+	"contains":    `"%s"%s LIKE ?`,
+	"icontains":   `"%s"%s ILIKE ?`,
+	"startswith":  `"%s"%s LIKE ?`,
+	"istartswith": `"%s"%s ILIKE ?`,
+	"endswith":    `"%s"%s LIKE ?`,
+	"iendswith":   `"%s"%s ILIKE ?`,
+}
+
+var Methods = map[string]string{
+	"toDate":      "?::date",
+	"toTimestamp": "?::timestamp",
+}
+
+// Inserts are the insert templates of the insert modes, PostgreSQL has no REPLACE.
+var Inserts = map[string]string{
+	operator.InsertIgnore: "INSERT INTO %s (%s) VALUES (%s) ON CONFLICT DO NOTHING",
+}
+
+// Locks are the row locking clauses appended to SELECT.
+var Locks = map[string]string{
+	operator.LockForUpdate:  " FOR UPDATE",
+	operator.LockForShare:   " FOR SHARE",
+	operator.LockSkipLocked: " SKIP LOCKED",
+	operator.LockNoWait:     " NOWAIT",
+}
@@ -0,0 +1,41 @@
+package sqlite
+
+import "github.com/leisurelicht/norm/internal/operator"
+
+// globPattern turns a LIKE pattern into a GLOB one, escaping GLOB wildcards first.
+// char(63) stands for "?" so it is not taken for a bind marker.
+const globPattern = `replace(replace(replace(replace(?, '[', '[[]'), '*', '[*]'), char(63), '[' || char(63) || ']'), '%%', '*')`
+
+// LIKE is case-insensitive in sqlite, GLOB is used for the case-sensitive lookups.
+var Operators = map[string]string{
+	"exact":   `"%s" = ?`,
+	"exclude": `"%s" != ?`,
+	"iexact":  `"%s" LIKE ?`,
+	"gt":      `"%s" > ?`,
+	"gte":     `"%s" >= ?`,
+	"lt":      `"%s" < ?`,
+	"lte":     `"%s" <= ?`,
+	"len":     `length("%s") = ?`,
+	"is_null": `"%s" IS NULL`,
+
+	"in":          `"%s"%s IN`,
+	"exists":      `%s EXISTS`,
+	"between":     `"%s"%s BETWEEN ? AND ?`,
+	"contains":    `"%s"%s GLOB ` + globPattern,
+	"icontains":   `"%s"%s LIKE ?`,
+	"startswith":  `"%s"%s GLOB ` + globPattern,
+	"istartswith": `"%s"%s LIKE ?`,
+	"endswith":    `"%s"%s GLOB ` + globPattern,
+	"iendswith":   `"%s"%s LIKE ?`,
+}
+
+var Methods = map[string]string{
+	"toDate":     "date(?)",
+	"toDateTime": "datetime(?)",
+}
+
+// Inserts are the insert templates of the insert modes.
+var Inserts = map[string]string{
+	operator.InsertIgnore:  "INSERT OR IGNORE INTO %s (%s) VALUES (%s)",
+	operator.InsertReplace: "INSERT OR REPLACE INTO %s (%s) VALUES (%s)",
+}
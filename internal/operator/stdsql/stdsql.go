@@ -0,0 +1,199 @@
+package stdsql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/leisurelicht/norm/internal/operator"
+)
+
+// Conn is the query interface shared by *sql.DB, *sql.Tx and *sql.Conn.
+type Conn interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+type fieldKey struct {
+	typ reflect.Type
+	tag string
+}
+
+// fieldCache caches the column name to field index mapping of a struct type and tag.
+var fieldCache sync.Map
+
+// fieldIndexes returns the column name to field index mapping of a struct type.
+// Anonymous struct fields without tag are flattened into the parent like sqlx does.
+func fieldIndexes(typ reflect.Type, tag string) map[string][]int {
+	key := fieldKey{typ: typ, tag: tag}
+	if v, ok := fieldCache.Load(key); ok {
+		return v.(map[string][]int)
+	}
+
+	indexes := make(map[string][]int, typ.NumField())
+	collectFieldIndexes(typ, tag, nil, indexes)
+
+	fieldCache.Store(key, indexes)
+	return indexes
+}
+
+func collectFieldIndexes(typ reflect.Type, tag string, parent []int, indexes map[string][]int) {
+	for i := range typ.NumField() {
+		fi := typ.Field(i)
+		tagv := fi.Tag.Get(tag)
+		if strings.Contains(tagv, ",") {
+			tagv = strings.TrimSpace(strings.Split(tagv, ",")[0])
+		}
+		if tagv == "-" {
+			continue
+		}
+
+		index := append(append(make([]int, 0, len(parent)+1), parent...), i)
+
+		if fi.Anonymous && tagv == "" && fi.Type.Kind() == reflect.Struct {
+			collectFieldIndexes(fi.Type, tag, index, indexes)
+			continue
+		}
+		if !fi.IsExported() {
+			continue
+		}
+		if tagv == "" {
+			tagv = fi.Name
+		}
+		if _, ok := indexes[tagv]; !ok {
+			indexes[tagv] = index
+		}
+	}
+}
+
+// scanStruct scans the current row into the struct value v.
+// Columns which have no matching field are discarded.
+func scanStruct(rows *sql.Rows, columns []string, v reflect.Value, tag string) error {
+	indexes := fieldIndexes(v.Type(), tag)
+
+	dest := make([]any, len(columns))
+	for i, column := range columns {
+		if index, ok := indexes[column]; ok {
+			dest[i] = v.FieldByIndex(index).Addr().Interface()
+			continue
+		}
+		dest[i] = new(any)
+	}
+
+	return rows.Scan(dest...)
+}
+
+// ScanRow scans the first row of rows into model, which must be a pointer to struct.
+// It returns operator.ErrNotFound if there is no row.
+func ScanRow(rows *sql.Rows, model any, tag string) error {
+	defer func() { _ = rows.Close() }()
+
+	rv := reflect.ValueOf(model)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("model must be a pointer to struct")
+	}
+
+	if !rows.Next() {
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		return operator.ErrNotFound
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	if err := scanStruct(rows, columns, rv.Elem(), tag); err != nil {
+		return err
+	}
+
+	return rows.Close()
+}
+
+// ScanRows scans all rows into model, which must be a pointer to a slice of struct or struct pointer.
+func ScanRows(rows *sql.Rows, model any, tag string) error {
+	defer func() { _ = rows.Close() }()
+
+	rv := reflect.ValueOf(model)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Slice {
+		return errors.New("model must be a pointer to slice")
+	}
+
+	sliceValue := rv.Elem()
+	elemType := sliceValue.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Pointer
+	if isPtr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		return errors.New("model must be a pointer to slice of struct")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		elem := reflect.New(elemType)
+		if err := scanStruct(rows, columns, elem.Elem(), tag); err != nil {
+			return err
+		}
+		if isPtr {
+			sliceValue.Set(reflect.Append(sliceValue, elem))
+		} else {
+			sliceValue.Set(reflect.Append(sliceValue, elem.Elem()))
+		}
+	}
+
+	return rows.Err()
+}
+
+// ScanEach scans the rows one by one into model, which must be a pointer to struct, and calls fn after each row.
+// It stops at the first error returned by fn and returns it.
+func ScanEach(rows *sql.Rows, model any, tag string, fn func() error) error {
+	defer func() { _ = rows.Close() }()
+
+	rv := reflect.ValueOf(model)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return errors.New("model must be a pointer to struct")
+	}
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+
+	for rows.Next() {
+		if err := scanStruct(rows, columns, rv.Elem(), tag); err != nil {
+			return err
+		}
+		if err := fn(); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// BulkValues flattens the rows of data into a single argument list ordered by columns.
+// Missing columns are filled with nil.
+func BulkValues(columns []string, data []map[string]any) []any {
+	values := make([]any, 0, len(columns)*len(data))
+	for _, row := range data {
+		for _, column := range columns {
+			if val, ok := row[column]; ok {
+				values = append(values, val)
+				continue
+			}
+			values = append(values, nil)
+		}
+	}
+	return values
+}
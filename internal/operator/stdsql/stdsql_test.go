@@ -0,0 +1,207 @@
+package stdsql
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/leisurelicht/norm/internal/operator"
+)
+
+// fakeDriver returns the same fixed result set for every query.
+type fakeDriver struct{}
+
+type fakeConn struct{}
+
+type fakeStmt struct{}
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	pos     int
+}
+
+var fakeResult = struct {
+	columns []string
+	values  [][]driver.Value
+}{
+	columns: []string{"id", "name", "extra", "create_by"},
+	values: [][]driver.Value{
+		{int64(1), "a", "x", "u1"},
+		{int64(2), "b", "y", "u2"},
+	},
+}
+
+func (fakeDriver) Open(string) (driver.Conn, error) { return fakeConn{}, nil }
+
+func (fakeConn) Prepare(string) (driver.Stmt, error) { return fakeStmt{}, nil }
+func (fakeConn) Close() error                        { return nil }
+func (fakeConn) Begin() (driver.Tx, error)           { return nil, errors.New("not supported") }
+
+func (fakeStmt) Close() error  { return nil }
+func (fakeStmt) NumInput() int { return -1 }
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("not supported")
+}
+func (fakeStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := &fakeRows{columns: fakeResult.columns, values: fakeResult.values}
+	if len(args) > 0 {
+		rows.values = nil
+	}
+	return rows, nil
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func init() {
+	sql.Register("norm_fake", fakeDriver{})
+}
+
+type base struct {
+	CreateBy string `db:"create_by"`
+}
+
+type model struct {
+	base
+	Id      int64  `db:"id"`
+	Name    string `db:"name,type=varchar"`
+	Ignored string `db:"-"`
+}
+
+func openFake(t *testing.T) *sql.DB {
+	db, err := sql.Open("norm_fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestScanRow(t *testing.T) {
+	db := openFake(t)
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got model
+	if err := ScanRow(rows, &got, "db"); err != nil {
+		t.Fatalf("ScanRow error: %v", err)
+	}
+	if got.Id != 1 || got.Name != "a" || got.CreateBy != "u1" {
+		t.Errorf("got %+v", got)
+	}
+
+	rows, err = db.Query("SELECT", "empty")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ScanRow(rows, &got, "db"); !errors.Is(err, operator.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, operator.ErrNotFound)
+	}
+}
+
+func TestScanRows(t *testing.T) {
+	db := openFake(t)
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []model
+	if err := ScanRows(rows, &got, "db"); err != nil {
+		t.Fatalf("ScanRows error: %v", err)
+	}
+	if len(got) != 2 || got[1].Id != 2 || got[1].Name != "b" || got[1].CreateBy != "u2" {
+		t.Errorf("got %+v", got)
+	}
+
+	rows, err = db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var gotPtr []*model
+	if err := ScanRows(rows, &gotPtr, "db"); err != nil {
+		t.Fatalf("ScanRows error: %v", err)
+	}
+	if len(gotPtr) != 2 || gotPtr[0].Id != 1 {
+		t.Errorf("got %+v", gotPtr)
+	}
+
+	rows, err = db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notSlice model
+	if err := ScanRows(rows, &notSlice, "db"); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestScanEach(t *testing.T) {
+	db := openFake(t)
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var (
+		got  model
+		seen []model
+	)
+	if err := ScanEach(rows, &got, "db", func() error {
+		seen = append(seen, got)
+		return nil
+	}); err != nil {
+		t.Fatalf("ScanEach error: %v", err)
+	}
+	if len(seen) != 2 || seen[0].Id != 1 || seen[1].Name != "b" || seen[1].CreateBy != "u2" {
+		t.Errorf("got %+v", seen)
+	}
+
+	rows, err = db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	stop := errors.New("stop")
+	calls := 0
+	if err := ScanEach(rows, &got, "db", func() error {
+		calls++
+		return stop
+	}); !errors.Is(err, stop) || calls != 1 {
+		t.Errorf("got error %v after %d calls, want %v after 1 call", err, calls, stop)
+	}
+
+	rows, err = db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var notStruct []model
+	if err := ScanEach(rows, &notStruct, "db", func() error { return nil }); err == nil {
+		t.Error("expected error, got nil")
+	}
+}
+
+func TestBulkValues(t *testing.T) {
+	got := BulkValues([]string{"id", "name"}, []map[string]any{{"id": 1, "name": "a"}, {"id": 2}})
+	want := []any{1, "a", 2, nil}
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
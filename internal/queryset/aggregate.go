@@ -0,0 +1,85 @@
+package queryset
+
+import (
+	"strings"
+)
+
+const (
+	AggCount = "COUNT"
+	AggSum   = "SUM"
+	AggAvg   = "AVG"
+	AggMax   = "MAX"
+	AggMin   = "MIN"
+)
+
+// Aggregation is an aggregate function over a column, e.g. Sum("amount") gives SUM(`amount`) AS `amount__sum`.
+type Aggregation struct {
+	function string
+	column   string
+	alias    string
+}
+
+// Count counts the rows, the column can be "*".
+func Count(column string) Aggregation {
+	return Aggregation{function: AggCount, column: column}
+}
+
+// Sum sums the values of the column.
+func Sum(column string) Aggregation {
+	return Aggregation{function: AggSum, column: column}
+}
+
+// Avg averages the values of the column.
+func Avg(column string) Aggregation {
+	return Aggregation{function: AggAvg, column: column}
+}
+
+// Max returns the maximum value of the column.
+func Max(column string) Aggregation {
+	return Aggregation{function: AggMax, column: column}
+}
+
+// Min returns the minimum value of the column.
+func Min(column string) Aggregation {
+	return Aggregation{function: AggMin, column: column}
+}
+
+// As sets the alias which is the key of the aggregation in the result.
+func (a Aggregation) As(alias string) Aggregation {
+	a.alias = alias
+	return a
+}
+
+func (a Aggregation) Function() string {
+	return a.function
+}
+
+func (a Aggregation) Column() string {
+	return a.column
+}
+
+// Alias returns the alias of the aggregation, it defaults to the column name and the function
+// joined by "__", like "amount__sum", and "count" for Count("*").
+func (a Aggregation) Alias() string {
+	if a.alias != "" {
+		return a.alias
+	}
+	function := strings.ToLower(a.function)
+	if a.column == "*" {
+		return function
+	}
+	column := a.column
+	if pos := strings.LastIndex(column, "."); pos != -1 {
+		column = column[pos+1:]
+	}
+	return column + operatorJoiner + function
+}
+
+// SQL renders the aggregation with its alias, columns are wrapped by quote.
+func (a Aggregation) SQL(quote func(column string) string) string {
+	column := a.column
+	if column != "*" {
+		column = quote(column)
+	}
+	return a.function + "(" + column + ") AS " + quote(a.Alias())
+}
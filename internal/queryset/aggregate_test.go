@@ -0,0 +1,38 @@
+package queryset
+
+import (
+	"testing"
+
+	"github.com/leisurelicht/norm/internal/operator"
+)
+
+func TestAggregationSQL(t *testing.T) {
+	quote := func(column string) string {
+		return operator.QuoteIdent("`", column)
+	}
+
+	tests := []struct {
+		name        string
+		aggregation Aggregation
+		alias       string
+		sql         string
+	}{
+		{"count_all", Count("*"), "count", "COUNT(*) AS `count`"},
+		{"count", Count("id"), "id__count", "COUNT(`id`) AS `id__count`"},
+		{"sum", Sum("amount"), "amount__sum", "SUM(`amount`) AS `amount__sum`"},
+		{"avg", Avg("age"), "age__avg", "AVG(`age`) AS `age__avg`"},
+		{"max", Max("create_time"), "create_time__max", "MAX(`create_time`) AS `create_time__max`"},
+		{"min_alias", Min("type").As("lowest"), "lowest", "MIN(`type`) AS `lowest`"},
+		{"qualified", Sum("property.id"), "id__sum", "SUM(`property`.`id`) AS `id__sum`"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if alias := tt.aggregation.Alias(); alias != tt.alias {
+				t.Errorf("got alias %q, want %q", alias, tt.alias)
+			}
+			if sql := tt.aggregation.SQL(quote); sql != tt.sql {
+				t.Errorf("got sql %q, want %q", sql, tt.sql)
+			}
+		})
+	}
+}
@@ -0,0 +1,85 @@
+package queryset
+
+import "strings"
+
+// Expr is a column expression, it can be the value of a filter lookup or of an update,
+// e.g. F("stock").Sub(1) gives `stock` - ? with 1 as arg.
+type Expr struct {
+	column string
+	ops    []exprOp
+}
+
+type exprOp struct {
+	operator string
+	value    any
+}
+
+// F returns an expression referring to the column, it may be qualified like "user.id".
+func F(column string) Expr {
+	return Expr{column: column}
+}
+
+func (e Expr) with(operator string, value any) Expr {
+	ops := make([]exprOp, len(e.ops), len(e.ops)+1)
+	copy(ops, e.ops)
+	e.ops = append(ops, exprOp{operator: operator, value: value})
+	return e
+}
+
+// Add adds a value or another expression to the expression.
+func (e Expr) Add(value any) Expr {
+	return e.with("+", value)
+}
+
+// Sub subtracts a value or another expression from the expression.
+func (e Expr) Sub(value any) Expr {
+	return e.with("-", value)
+}
+
+// Mul multiplies the expression by a value or another expression.
+func (e Expr) Mul(value any) Expr {
+	return e.with("*", value)
+}
+
+// Div divides the expression by a value or another expression.
+func (e Expr) Div(value any) Expr {
+	return e.with("/", value)
+}
+
+// Columns returns the columns the expression refers to, with the ones of the nested expressions.
+func (e Expr) Columns() []string {
+	columns := []string{e.column}
+	for _, op := range e.ops {
+		if expr, ok := op.value.(Expr); ok {
+			columns = append(columns, expr.Columns()...)
+		}
+	}
+	return columns
+}
+
+// SQL renders the expression, columns are wrapped by quote and values are returned as args.
+func (e Expr) SQL(quote func(column string) string) (sql string, args []any) {
+	var b strings.Builder
+	b.WriteString(strings.Repeat("(", max(len(e.ops)-1, 0)))
+	b.WriteString(quote(e.column))
+
+	for i, op := range e.ops {
+		b.WriteString(" " + op.operator + " ")
+		if expr, ok := op.value.(Expr); ok {
+			exprSQL, exprArgs := expr.SQL(quote)
+			if len(expr.ops) > 0 {
+				exprSQL = "(" + exprSQL + ")"
+			}
+			b.WriteString(exprSQL)
+			args = append(args, exprArgs...)
+		} else {
+			b.WriteString(bindMarker)
+			args = append(args, op.value)
+		}
+		if i < len(e.ops)-1 {
+			b.WriteString(")")
+		}
+	}
+
+	return b.String(), args
+}
@@ -0,0 +1,81 @@
+package queryset
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/leisurelicht/norm/internal/operator"
+	ch_go "github.com/leisurelicht/norm/operator/clickhouse/clickhouse-go"
+)
+
+func TestExprSQL(t *testing.T) {
+	quote := func(column string) string {
+		return operator.QuoteIdent("`", column)
+	}
+
+	tests := []struct {
+		name string
+		expr Expr
+		sql  string
+		args []any
+	}{
+		{"column", F("stock"), "`stock`", nil},
+		{"sub", F("stock").Sub(1), "`stock` - ?", []any{1}},
+		{"chain", F("price").Add(1).Mul(2).Div(3), "((`price` + ?) * ?) / ?", []any{1, 2, 3}},
+		{"column_operand", F("price").Mul(F("count")), "`price` * `count`", nil},
+		{"expr_operand", F("total").Sub(F("price").Mul(2)), "`total` - (`price` * ?)", []any{2}},
+		{"qualified", F("user.score").Add(1), "`user`.`score` + ?", []any{1}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args := tt.expr.SQL(quote)
+			if sql != tt.sql {
+				t.Errorf("got sql %q, want %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("got args %v, want %v", args, tt.args)
+			}
+		})
+	}
+
+	base := F("stock").Add(1)
+	_ = base.Sub(2)
+	if sql, _ := base.Add(3).SQL(quote); sql != "(`stock` + ?) + ?" {
+		t.Errorf("got sql %q, expression should not be shared", sql)
+	}
+}
+
+func TestExprFilter(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Cond
+		sql    string
+		args   []any
+		err    string
+	}{
+		{"exact", Cond{"stock": F("sold")}, " WHERE (`stock` = `sold`)", nil, ""},
+		{"gt", Cond{"update_time__gt": F("create_time")}, " WHERE (`update_time` > `create_time`)", nil, ""},
+		{"lte_arithmetic", Cond{"sold__lte": F("stock").Sub(1)}, " WHERE (`sold` <= `stock` - ?)", []any{1}, ""},
+		{"method", Cond{"day__exact##toDate": F("create_time")}, " WHERE (`day` = toDate(`create_time`))", nil, ""},
+		{"unsupported", Cond{"name__contains": F("title")}, "", nil, "operator [contains] unsupported value type [expression]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewQuerySet(ch_go.NewOperator(nil))
+			p.FilterToSQL(NotNot, tt.filter)
+			if tt.err != "" {
+				if p.Error() == nil || p.Error().Error() != tt.err {
+					t.Fatalf("got error %v, want %s", p.Error(), tt.err)
+				}
+				return
+			}
+			sql, args := p.GetQuerySet()
+			if sql != tt.sql {
+				t.Errorf("got sql %q, want %q", sql, tt.sql)
+			}
+			if len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+				t.Errorf("got args %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
@@ -25,3 +25,15 @@ func EachOR[T Cond | AND | OR](conditions T) T {
 	}
 	return conditions
 }
+
+// LookupField returns the field name of a filter key, e.g. "| user.name__contains" gives "user.name".
+func LookupField(key string) string {
+	key = strings.TrimPrefix(key, OrPrefix)
+	if pos := strings.Index(key, methodJoiner); pos != -1 {
+		key = key[:pos]
+	}
+	if pos := strings.Index(key, operatorJoiner); pos != -1 {
+		key = key[:pos]
+	}
+	return strings.TrimSpace(key)
+}
@@ -73,3 +73,27 @@ func TestEachOR(t *testing.T) {
 		})
 	}
 }
+
+func TestLookupField(t *testing.T) {
+	type args struct {
+		key string
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{"field", args{"name"}, "name"},
+		{"operator", args{"name__contains"}, "name"},
+		{"qualified", args{"user.name__not_contains"}, "user.name"},
+		{"or", args{"| user.name"}, "user.name"},
+		{"method", args{"create_time__gte##toDate"}, "create_time"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := LookupField(tt.args.key); got != tt.want {
+				t.Errorf("LookupField() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
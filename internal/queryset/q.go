@@ -0,0 +1,104 @@
+package queryset
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Q is a condition tree, its conditions are Cond / AND / OR or nested Q and are joined by AND or OR,
+// e.g. Or(Cond{"name": "a"}, And(Cond{"type": 1}, Not(Cond{"id__in": []int{1, 2}}))).
+// The conjunction of a Cond / AND / OR inside a Q is decided by the Q, the keys of one map are
+// still joined by AND unless they have the OrPrefix.
+type Q struct {
+	conj       int
+	not        bool
+	conditions []any
+}
+
+// And returns a Q matching all of the conditions.
+func And(conditions ...any) Q {
+	return Q{conj: andTag, conditions: conditions}
+}
+
+// Or returns a Q matching any of the conditions.
+func Or(conditions ...any) Q {
+	return Q{conj: orTag, conditions: conditions}
+}
+
+// Not returns a Q matching rows which do not match all of the conditions.
+func Not(conditions ...any) Q {
+	return Q{conj: andTag, not: true, conditions: conditions}
+}
+
+// And returns a Q matching both the q and all of the conditions.
+func (q Q) And(conditions ...any) Q {
+	return And(append([]any{q}, conditions...)...)
+}
+
+// Or returns a Q matching either the q or any of the conditions.
+func (q Q) Or(conditions ...any) Q {
+	return Or(append([]any{q}, conditions...)...)
+}
+
+// Not returns the negation of the q.
+func (q Q) Not() Q {
+	q.not = !q.not
+	return q
+}
+
+// Conditions returns the direct conditions of the q.
+func (q Q) Conditions() []any {
+	return q.conditions
+}
+
+// qHandler renders the q, every condition is wrapped by parentheses when there are more than one,
+// and the args follow the order of the conditions.
+func (p *QuerySetImpl) qHandler(q Q) (qSQL string, qArgs []any) {
+	parts := make([]string, 0, len(q.conditions))
+
+	for _, c := range q.conditions {
+		var (
+			condSQL  string
+			condArgs []any
+		)
+		switch v := c.(type) {
+		case Cond:
+			condSQL, condArgs = p.filterHandler(v)
+		case AND:
+			condSQL, condArgs = p.filterHandler(v)
+		case OR:
+			condSQL, condArgs = p.filterHandler(v)
+		case Q:
+			condSQL, condArgs = p.qHandler(v)
+		case nil:
+			p.SetError(UnsupportedFilterTypeError, "nil")
+			return "", nil
+		default:
+			p.SetError(UnsupportedFilterTypeError, reflect.TypeOf(c).String())
+			return "", nil
+		}
+		if p.err != nil {
+			return "", nil
+		}
+		if condSQL == "" {
+			continue
+		}
+		parts = append(parts, condSQL)
+		qArgs = append(qArgs, condArgs...)
+	}
+
+	switch len(parts) {
+	case 0:
+		return "", nil
+	case 1:
+		qSQL = parts[0]
+	default:
+		qSQL = "(" + strings.Join(parts, ") "+conjunctions[q.conj]+" (") + ")"
+	}
+
+	if q.not {
+		qSQL = "NOT (" + qSQL + ")"
+	}
+
+	return qSQL, qArgs
+}
@@ -0,0 +1,56 @@
+package queryset
+
+import (
+	"reflect"
+	"testing"
+
+	ch_go "github.com/leisurelicht/norm/operator/clickhouse/clickhouse-go"
+)
+
+func TestQ(t *testing.T) {
+	tests := []struct {
+		name   string
+		state  int
+		filter []any
+		sql    string
+		args   []any
+		err    string
+	}{
+		{"and", NotNot, []any{And(Cond{"name": "a"}, Cond{"type": 1})}, " WHERE ((`name` = ?) AND (`type` = ?))", []any{"a", 1}, ""},
+		{"or", NotNot, []any{Or(Cond{"name": "a"}, Cond{"type": 1})}, " WHERE ((`name` = ?) OR (`type` = ?))", []any{"a", 1}, ""},
+		{"not", NotNot, []any{Not(Cond{"name": "a"})}, " WHERE (NOT (`name` = ?))", []any{"a"}, ""},
+		{"nested", NotNot, []any{Or(Cond{"name": "a"}, And(Cond{"type": 1}, Not(Cond{"id__in": []int{1, 2}})))},
+			" WHERE ((`name` = ?) OR ((`type` = ?) AND (NOT (`id` in (?,?)))))", []any{"a", 1, 1, 2}, ""},
+		{"chain", NotNot, []any{And(Cond{"name": "a"}).Or(Cond{"type": 1}).Not()}, " WHERE (NOT ((`name` = ?) OR (`type` = ?)))", []any{"a", 1}, ""},
+		{"multi_key_leaf", NotNot, []any{Or(Cond{"name": "a", "type": 1, SortKey: []string{"name", "type"}}, Cond{"id": 3})},
+			" WHERE ((`name` = ? AND `type` = ?) OR (`id` = ?))", []any{"a", 1, 3}, ""},
+		{"with_cond", NotNot, []any{Cond{"id__gt": 1}, OR{"name": "b"}, Or(Cond{"type": 1}, Cond{"type": 2})},
+			" WHERE ((`id` > ?) OR (`name` = ?) AND ((`type` = ?) OR (`type` = ?)))", []any{1, "b", 1, 2}, ""},
+		{"exclude", IsNot, []any{Or(Cond{"name": "a"}, Cond{"type": 1})}, " WHERE NOT ((`name` = ?) OR (`type` = ?))", []any{"a", 1}, ""},
+		{"empty", NotNot, []any{And(Cond{}, Or())}, "", nil, ""},
+		{"nil", NotNot, []any{And(Cond{"name": "a"}, nil)}, "", nil, "unsupported filter type [nil], Please use be [Cond | AND | OR | Q]"},
+		{"unsupported", NotNot, []any{Or(Cond{"name": "a"}, map[string]any{"type": 1})}, "", nil, "unsupported filter type [map[string]interface {}], Please use be [Cond | AND | OR | Q]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewQuerySet(ch_go.NewOperator(nil))
+			p.FilterToSQL(tt.state, tt.filter...)
+			if tt.err != "" {
+				if p.Error() == nil || p.Error().Error() != tt.err {
+					t.Fatalf("got error %v, want %s", p.Error(), tt.err)
+				}
+				return
+			}
+			if p.Error() != nil {
+				t.Fatalf("got error %v", p.Error())
+			}
+			sql, args := p.GetQuerySet()
+			if sql != tt.sql {
+				t.Errorf("got sql %q, want %q", sql, tt.sql)
+			}
+			if len(args) != len(tt.args) || (len(args) > 0 && !reflect.DeepEqual(args, tt.args)) {
+				t.Errorf("got args %v, want %v", args, tt.args)
+			}
+		})
+	}
+}
@@ -15,6 +15,16 @@ type (
 	OR   map[string]any
 )
 
+// Subquery is a query which can be the value of the exists lookup, e.g. a Controller.
+type Subquery interface {
+	QuerySQL() (query string, args []any, err error)
+}
+
+// ColumnSubquery is a query of a single column which can be the value of the in lookup, e.g. a Controller.
+type ColumnSubquery interface {
+	ColumnSQL() (query string, args []any, err error)
+}
+
 const (
 	defaultOuterFilterCondsLen = 10
 	defaultInnerFilterCondsLen = 10
@@ -25,6 +35,8 @@ const (
 	plural                     = "~"
 	methodJoiner               = "##"
 	SortKey                    = "~sort~"
+	// bindMarker is the dialect neutral placeholder, operators rebind it by their placeholder option.
+	bindMarker = "?"
 )
 
 const (
@@ -47,6 +59,7 @@ const (
 	_iendswith   = "iendswith"
 
 	_isNull = "is_null"
+	_exists = "exists"
 )
 
 const (
@@ -56,8 +69,8 @@ const (
 	isNotValueError       = "IsNot value must be 0 or 1"
 	paramTypeError        = "param type must be string or slice of string"
 	pageSizeORNumberError = "page size and page number must be positive"
+	seekValuesLenError    = "seek values length must be equal to order key length"
 
-	FilterOrWhereError          = "[%s] or [Where] can not be called at the same time"
 	fieldLookupError            = "field lookups [%s] is invalid"
 	unknownOperatorError        = "unknown operator [%s]"
 	notImplementedOperatorError = "not implemented operator [%s]"
@@ -65,8 +78,10 @@ const (
 	operatorValueLenError       = "operator [%s] value length must be [%d]"
 	operatorValueLenLessError   = "operator [%s] value length must greater than [%d]"
 	operatorValueTypeError      = "operator [%s] value must be string list"
-	UnsupportedFilterTypeError  = "unsupported filter type [%s], Please use be [Cond | AND | OR]"
+	UnsupportedFilterTypeError  = "unsupported filter type [%s], Please use be [Cond | AND | OR | Q]"
 	operatorValueEmptyError     = "operator [%s] unsupported value empty"
+	subqueryError               = "operator [%s] subquery error: %w"
+	inStringValueError          = "operator [%s] does not accept a raw string, use a slice or a subquery"
 )
 
 type CallFlag int64
@@ -80,6 +95,15 @@ const (
 	QsSelect
 	QsGroupBy
 	QsHaving
+	QsJoin
+	QsAnnotate
+	QsDistinct
+	QsAfter
+	QsBefore
+	QsForUpdate
+	QsForShare
+	QsWithDeleted
+	QsOnlyDeleted
 )
 
 const (
@@ -90,14 +114,14 @@ const (
 )
 
 var (
-	filterAndExclude = []string{"Filter", "Exclude"}
-	not              = [2]string{"", " NOT"}
-	conjunctions     = [4]string{"AND", "OR", "AND NOT", "OR NOT"}
+	not          = [2]string{"", " NOT"}
+	conjunctions = [4]string{"AND", "OR", "AND NOT", "OR NOT"}
 	// Define a map for conjunction types and their tags
 	conjunctionMap = map[reflect.Type]int{
 		reflect.TypeOf(Cond{}): andTag,
 		reflect.TypeOf(AND{}):  andTag,
 		reflect.TypeOf(OR{}):   orTag,
+		reflect.TypeOf(Q{}):    andTag,
 	}
 )
 
@@ -105,6 +129,8 @@ type cond struct {
 	Conj string
 	SQL  string
 	Args []any
+	// Raw is set for the conditions of Where, they are written as they are.
+	Raw bool
 }
 
 func newCond() *cond {
@@ -139,6 +165,7 @@ type QuerySet interface {
 	GetQuerySet() (string, []any)
 	FilterToSQL(notTag int, filter ...any) QuerySet
 	WhereToSQL(cond string, args ...any) QuerySet
+	OrWhereToSQL(cond string, args ...any) QuerySet
 	GetSelectSQL() string
 	SelectToSQL(columns any) QuerySet
 	StrSelectToSQL(columns string) QuerySet
@@ -149,24 +176,29 @@ type QuerySet interface {
 	OrderByToSQL(orderBy any) QuerySet
 	StrOrderByToSQL(orderBy string) QuerySet
 	SliceOrderByToSQL(orderBy []string) QuerySet
+	SeekToSQL(orderBy []string, values []any, before bool) QuerySet
 	GetGroupBySQL() string
 	GroupByToSQL(groupBy any) QuerySet
 	StrGroupByToSQL(groupBy string) QuerySet
 	SliceGroupByToSQL(groupBy []string) QuerySet
 	GetHavingSQL() (string, []any)
 	HavingToSQL(having string, args ...any) QuerySet
+	GetJoinSQL() (string, []any)
+	JoinToSQL(table, join string, args ...any) QuerySet
 }
 
 type QuerySetImpl struct {
 	operator.Operator
 	selectColumn  string
-	whereCond     cond
 	filterConds   [][]cond
 	filterConjTag []int
 	orderBySQL    string
 	limitSQL      string
 	groupSQL      string
 	havingSQL     cond
+	seekCond      cond
+	joinSQL       cond
+	qualifier     string
 	err           error
 	called        CallFlag
 }
@@ -177,7 +209,6 @@ func NewQuerySet(op operator.Operator) QuerySet {
 	return &QuerySetImpl{
 		Operator:      op,
 		selectColumn:  "*",
-		whereCond:     cond{},
 		filterConds:   make([][]cond, 0, defaultOuterFilterCondsLen),
 		filterConjTag: make([]int, 0, defaultOuterFilterCondsLen),
 		orderBySQL:    "",
@@ -187,6 +218,26 @@ func NewQuerySet(op operator.Operator) QuerySet {
 	}
 }
 
+// qualify prefixes a column with the main table once a join is added, so it is not ambiguous.
+func (p *QuerySetImpl) qualify(column string) string {
+	if p.qualifier == "" || strings.Contains(column, ".") {
+		return column
+	}
+	return p.qualifier + "." + column
+}
+
+// quote wraps a column name with the identifier quote of the operator's dialect.
+func (p *QuerySetImpl) quote(column string) string {
+	return operator.QuoteIdent(p.GetQuote(), p.qualify(column))
+}
+
+// column returns the field name for the operator templates, which wrap it with quotes themselves,
+// e.g. user.name gives user`.`name to be formatted into `user`.`name`.
+func (p *QuerySetImpl) column(fieldName string) string {
+	quote := p.GetQuote()
+	return strings.ReplaceAll(p.qualify(fieldName), ".", quote+"."+quote)
+}
+
 func (p *QuerySetImpl) setCalled(f CallFlag) {
 	p.called = p.called | f
 }
@@ -205,28 +256,52 @@ func (p *QuerySetImpl) Error() error {
 
 func (p *QuerySetImpl) Reset() {
 	p.selectColumn = "*"
-	p.whereCond = cond{}
 	p.filterConds = make([][]cond, 0, defaultOuterFilterCondsLen)
 	p.filterConjTag = make([]int, 0, defaultOuterFilterCondsLen)
 	p.orderBySQL = ""
 	p.limitSQL = ""
 	p.groupSQL = ""
 	p.havingSQL = cond{}
+	p.seekCond = cond{}
+	p.joinSQL = cond{}
+	p.qualifier = ""
 	p.err = nil
 	p.called = 0
 }
 
+// GetQuerySet returns the WHERE clause of the conditions and its args. The keyset condition of
+// After / Before is joined with the other conditions by AND, they are wrapped in parentheses
+// first, so an OR among them can not return the rows before the cursor.
 func (p *QuerySetImpl) GetQuerySet() (sql string, args []any) {
-	// Handle the case with direct WHERE condition
-	if p.whereCond.SQL != "" {
-		return " WHERE " + p.whereCond.SQL, p.whereCond.Args
+	sql, args = p.conditionSQL()
+	if p.seekCond.SQL == "" {
+		if sql == "" {
+			return "", nil
+		}
+		return " WHERE " + sql, args
 	}
 
+	if sql == "" {
+		return " WHERE (" + p.seekCond.SQL + ")", p.seekCond.Args
+	}
+	if len(p.filterConds) > 1 || p.filterConds[0][0].Raw {
+		sql = "(" + sql + ")"
+	}
+	return " WHERE " + sql + " AND (" + p.seekCond.SQL + ")", append(args, p.seekCond.Args...)
+}
+
+// conditionSQL returns the conditions of Filter, Exclude and Where without the WHERE keyword.
+func (p *QuerySetImpl) conditionSQL() (sql string, args []any) {
 	// Early return for no filter conditions
 	if len(p.filterConds) == 0 {
 		return "", nil
 	}
 
+	// A single Where condition is written as it is
+	if len(p.filterConds) == 1 && p.filterConds[0][0].Raw {
+		return p.filterConds[0][0].SQL, p.filterConds[0][0].Args
+	}
+
 	// Pre-calculate approximate capacity for string builder
 	totalConditions := 0
 	for _, filterList := range p.filterConds {
@@ -236,13 +311,26 @@ func (p *QuerySetImpl) GetQuerySet() (sql string, args []any) {
 	// Initial capacity estimate: 8 chars per condition + conjunctions
 	outerSQL := strings.Builder{}
 	outerSQL.Grow(totalConditions*20 + len(p.filterConds)*10)
-	outerSQL.WriteString(" WHERE ")
 
 	args = make([]any, 0, totalConditions*2) // Estimate arg count
 
+	// The groups are joined from left to right, an AND group after an OR group joins the whole
+	// expression before it, e.g. Where(a).OrWhere(b).Filter(c) gives ((a) OR (b)) AND (c).
+	hasOr := false
 	for i, condList := range p.filterConds {
 		// Add conjunction between filter groups
 		if i > 0 {
+			switch p.filterConjTag[i] {
+			case orTag:
+				hasOr = true
+			case andTag:
+				if hasOr {
+					prev := outerSQL.String()
+					outerSQL.Reset()
+					outerSQL.WriteString("(" + prev + ")")
+					hasOr = false
+				}
+			}
 			outerSQL.WriteString(" ")
 			outerSQL.WriteString(conjunctions[p.filterConjTag[i]])
 			outerSQL.WriteString(" ")
@@ -305,6 +393,7 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 		skList      []string
 		isOrder     = false
 		fieldName   string
+		column      string
 		operator    string
 		andOrFlag   = andTag
 		notFlag     = NotNot
@@ -364,31 +453,44 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 		}
 
 		fieldName = lookup[0]
+		column = p.column(fieldName)
 
 		fCond := newCond()
 		fCond.SetConj(conjunctions[andOrFlag])
 
+		if expr, ok := fieldValue.(Expr); ok {
+			switch operator {
+			case _exact, _exclude, _iexact, _gt, _gte, _lt, _lte:
+				exprSQL, exprArgs := expr.SQL(p.quote)
+				filterConds[fieldName] = fCond.SetSQL(strings.Replace(fmt.Sprintf(op, column), bindMarker, exprSQL, 1), exprArgs)
+				continue
+			default:
+				p.SetError(unsupportedValueError, operator, "expression")
+				return
+			}
+		}
+
 		valueOf := reflect.ValueOf(fieldValue)
 		valueKind := valueOf.Kind()
 		switch operator {
 		case _exact:
 			if fieldValue == nil {
 				// should generate sql like "fieldName IS NULL"
-				filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(p.OperatorSQL(_isNull, ""), fieldName), []any{})
+				filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(p.OperatorSQL(_isNull, ""), column), []any{})
 				break
 			}
 			// the value arrived here is not nil, so go to the next case for processing
 			fallthrough
 		case _exclude, _iexact:
 			if isStringKind(valueKind) || isBoolKind(valueKind) || isNumericKind(valueKind) {
-				filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(op, fieldName), []any{fieldValue})
+				filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(op, column), []any{fieldValue})
 			} else if isListKind(valueKind) {
 				if valueOf.Len() == 0 {
 					p.SetError(operatorValueLenLessError, operator, 0)
 					return
 				}
-				opStr := " " + conjunctions[0] + " " + fmt.Sprintf(op, fieldName)
-				sql := fmt.Sprintf(op, fieldName) + strings.Repeat(opStr, valueOf.Len()-1)
+				opStr := " " + conjunctions[0] + " " + fmt.Sprintf(op, column)
+				sql := fmt.Sprintf(op, column) + strings.Repeat(opStr, valueOf.Len()-1)
 				if len(filter) > 1 {
 					sql = "(" + sql + ")"
 				}
@@ -406,13 +508,23 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 				p.SetError(unsupportedValueError, operator, valueKind.String())
 				return
 			}
-			filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(op, fieldName), []any{fieldValue})
+			filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(op, column), []any{fieldValue})
 		case _in:
-			if isStringKind(valueKind) {
-				sql := fmt.Sprintf(op, fieldName, not[notFlag]) + " (" + fieldValue.(string) + ")"
-				filterConds[fieldName] = fCond.SetSQL(sql, []any{})
+			if sub, ok := fieldValue.(ColumnSubquery); ok {
+				subSQL, subArgs, err := sub.ColumnSQL()
+				if err != nil {
+					p.SetError(subqueryError, operator, err)
+					return
+				}
+				sql := fmt.Sprintf(op, column, not[notFlag]) + " (" + subSQL + ")"
+				filterConds[fieldName] = fCond.SetSQL(sql, subArgs)
 				continue
 			}
+			// a raw string was pasted into the SQL, it is rejected as it can not be bound as args
+			if isStringKind(valueKind) {
+				p.SetError(inStringValueError, operator)
+				return
+			}
 
 			if !isListKind(valueKind) {
 				p.SetError(unsupportedValueError, operator, valueKind.String())
@@ -422,7 +534,7 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 				p.SetError(operatorValueLenLessError, operator, 0)
 				return
 			}
-			sql := fmt.Sprintf(op, fieldName, not[notFlag]) + " (" + p.GetPlaceholder() + strings.Repeat(","+p.GetPlaceholder(), valueOf.Len()-1) + ")"
+			sql := fmt.Sprintf(op, column, not[notFlag]) + " (" + bindMarker + strings.Repeat(","+bindMarker, valueOf.Len()-1) + ")"
 			args := make([]any, valueOf.Len())
 			for i := 0; i < valueOf.Len(); i++ {
 				args[i] = valueOf.Index(i).Interface()
@@ -437,12 +549,25 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 				p.SetError(operatorValueLenError, operator, 2)
 				return
 			}
-			sql := fmt.Sprintf(op, fieldName, not[notFlag])
+			sql := fmt.Sprintf(op, column, not[notFlag])
 			args := make([]any, valueOf.Len())
 			for i := 0; i < valueOf.Len(); i++ {
 				args[i] = valueOf.Index(i).Interface()
 			}
 			filterConds[fieldName] = fCond.SetSQL(sql, args)
+		case _exists:
+			sub, ok := fieldValue.(Subquery)
+			if !ok {
+				p.SetError(unsupportedValueError, operator, valueKind.String())
+				return
+			}
+			subSQL, subArgs, err := sub.QuerySQL()
+			if err != nil {
+				p.SetError(subqueryError, operator, err)
+				return
+			}
+			sql := fmt.Sprintf(op, not[notFlag]) + " (" + subSQL + ")"
+			filterConds[fieldName] = fCond.SetSQL(strings.TrimSpace(sql), subArgs)
 		case _contains, _icontains, _startswith, _istartswith, _endswith, _iendswith:
 			valueFormat := "%%%v%%"
 			switch operator {
@@ -457,7 +582,7 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 					p.SetError(unsupportedValueError, operator, "blank string")
 					return
 				}
-				filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(op, fieldName, not[notFlag]), []any{fmt.Sprintf(valueFormat, fieldValue)})
+				filterConds[fieldName] = fCond.SetSQL(fmt.Sprintf(op, column, not[notFlag]), []any{fmt.Sprintf(valueFormat, fieldValue)})
 			} else if isListKind(valueKind) {
 				if valueOf.Len() == 0 {
 					p.SetError(operatorValueLenLessError, operator, 0)
@@ -467,7 +592,7 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 					p.SetError(operatorValueTypeError, operator)
 					return
 				}
-				genStrListValueLikeSQL(p, filterConds, fieldName, valueOf, notFlag, operator, valueFormat)
+				genStrListValueLikeSQL(p, filterConds, fieldName, column, valueOf, notFlag, operator, valueFormat)
 			} else {
 				p.SetError(unsupportedValueError, operator, valueKind.String())
 				return
@@ -500,18 +625,13 @@ func (p *QuerySetImpl) filterHandler(filter map[string]any) (filterSql string, f
 }
 
 func (p *QuerySetImpl) FilterToSQL(state int, filter ...any) QuerySet {
-	if !p.hasCalled(QsWhere) {
-		switch state {
-		case isFilter:
-			p.setCalled(QsFilter)
-		case isExclude:
-			p.setCalled(QsExclude)
-		default:
-			p.SetError(isNotValueError)
-			return p
-		}
-	} else {
-		p.SetError(FilterOrWhereError, filterAndExclude[state])
+	switch state {
+	case isFilter:
+		p.setCalled(QsFilter)
+	case isExclude:
+		p.setCalled(QsExclude)
+	default:
+		p.SetError(isNotValueError)
 		return p
 	}
 
@@ -542,6 +662,10 @@ func (p *QuerySetImpl) FilterToSQL(state int, filter ...any) QuerySet {
 			p.filterConjTag = append(p.filterConjTag, conjTag)
 		}
 
+		var (
+			filterSQL  string
+			filterArgs []any
+		)
 		switch v := f.(type) {
 		case Cond:
 			arg, conjFlag = v, andTag
@@ -549,11 +673,18 @@ func (p *QuerySetImpl) FilterToSQL(state int, filter ...any) QuerySet {
 			arg, conjFlag = v, andTag
 		case OR:
 			arg, conjFlag = v, orTag
+		case Q:
+			arg, conjFlag = nil, andTag
+			filterSQL, filterArgs = p.qHandler(v)
 		default:
 			p.SetError(UnsupportedFilterTypeError, reflect.TypeOf(f).String())
 		}
 
-		if filterSQL, filterArgs := p.filterHandler(arg); filterSQL == "" {
+		if arg != nil {
+			filterSQL, filterArgs = p.filterHandler(arg)
+		}
+
+		if filterSQL == "" {
 			continue
 		} else {
 			// Only add "NOT" to the conjunction for the first condition
@@ -573,24 +704,30 @@ func (p *QuerySetImpl) FilterToSQL(state int, filter ...any) QuerySet {
 	return p
 }
 
+// WhereToSQL adds a raw condition, it is joined with the Filter / Exclude conditions by AND.
 func (p *QuerySetImpl) WhereToSQL(cond string, args ...any) QuerySet {
-	if !p.hasCalled(QsFilter) && !p.hasCalled(QsExclude) {
-		p.setCalled(QsWhere)
-	} else if p.hasCalled(QsFilter) {
-		p.SetError(FilterOrWhereError, filterAndExclude[isFilter])
-		return p
-	} else if p.hasCalled(QsExclude) {
-		p.SetError(FilterOrWhereError, filterAndExclude[isExclude])
-		return p
-	}
+	return p.whereToSQL(andTag, cond, args)
+}
+
+// OrWhereToSQL adds a raw condition, it is joined with the previous conditions by OR.
+func (p *QuerySetImpl) OrWhereToSQL(cond string, args ...any) QuerySet {
+	return p.whereToSQL(orTag, cond, args)
+}
+
+func (p *QuerySetImpl) whereToSQL(conjTag int, where string, args []any) QuerySet {
+	p.setCalled(QsWhere)
 
-	num := strings.Count(cond, "?")
+	num := strings.Count(where, "?")
 	if (num == 0 && len(args) > 0) || (num > 0 && len(args) != num) {
 		p.SetError(argsLenError)
 		return p
 	}
-	p.whereCond.SQL = cond
-	p.whereCond.Args = args
+	if where == "" {
+		return p
+	}
+
+	p.filterConjTag = append(p.filterConjTag, conjTag)
+	p.filterConds = append(p.filterConds, []cond{{Conj: conjunctions[conjTag], SQL: where, Args: args, Raw: true}})
 
 	return p
 }
@@ -631,10 +768,10 @@ func (p *QuerySetImpl) SliceSelectToSQL(columns []string) QuerySet {
 	var result strings.Builder
 	result.Grow(len(columns) * 10) // Pre-allocate space for performance
 	for i := 0; i < len(columns)-1; i++ {
-		result.WriteString(wrapWithBackticks(columns[i]))
+		result.WriteString(p.quote(columns[i]))
 		result.WriteString(", ")
 	}
-	result.WriteString(wrapWithBackticks(columns[len(columns)-1]))
+	result.WriteString(p.quote(columns[len(columns)-1]))
 
 	p.selectColumn = result.String()
 
@@ -688,9 +825,9 @@ func (p *QuerySetImpl) SliceOrderByToSQL(orderBy []string) QuerySet {
 		by = strings.TrimSpace(by)
 		switch strings.HasPrefix(by, descPrefix) {
 		case true:
-			p.orderBySQL += "`" + by[1:] + "` DESC"
+			p.orderBySQL += p.quote(by[1:]) + " DESC"
 		case false:
-			p.orderBySQL += "`" + by + "` ASC"
+			p.orderBySQL += p.quote(by) + " ASC"
 		}
 		p.orderBySQL += ", "
 	}
@@ -700,6 +837,85 @@ func (p *QuerySetImpl) SliceOrderByToSQL(orderBy []string) QuerySet {
 	return p
 }
 
+// SeekToSQL adds the keyset condition of the rows after the values of the orderBy columns, e.g.
+// (`type`, `id`) > (?, ?) for []string{"type", "id"}. Mixed directions are expanded to
+// `type` > ? OR (`type` = ? AND `id` < ?) for []string{"type", "-id"}.
+// If before is true it adds the condition of the rows before the values and reverses the ORDER BY,
+// so the nearest rows come first and LIMIT keeps them.
+func (p *QuerySetImpl) SeekToSQL(orderBy []string, values []any, before bool) QuerySet {
+	if before {
+		p.setCalled(QsBefore)
+	} else {
+		p.setCalled(QsAfter)
+	}
+
+	if len(orderBy) == 0 || len(orderBy) != len(values) {
+		p.SetError(seekValuesLenError)
+		return p
+	}
+
+	var (
+		columns   = make([]string, len(orderBy))
+		operators = make([]string, len(orderBy))
+		reversed  = make([]string, len(orderBy))
+		mixed     bool
+	)
+	for i, by := range orderBy {
+		by = strings.TrimSpace(by)
+		desc := strings.HasPrefix(by, descPrefix)
+		columns[i] = p.quote(strings.TrimPrefix(by, descPrefix))
+		if desc {
+			reversed[i] = by[1:]
+		} else {
+			reversed[i] = descPrefix + by
+		}
+		if desc == before {
+			operators[i] = ">"
+		} else {
+			operators[i] = "<"
+		}
+		mixed = mixed || operators[i] != operators[0]
+	}
+
+	var (
+		seekSQL  string
+		seekArgs []any
+	)
+	switch {
+	case len(columns) == 1:
+		seekSQL, seekArgs = columns[0]+" "+operators[0]+" "+bindMarker, values
+	case !mixed:
+		seekSQL = "(" + strings.Join(columns, ", ") + ") " + operators[0] + " (" +
+			strings.TrimSuffix(strings.Repeat(bindMarker+", ", len(columns)), ", ") + ")"
+		seekArgs = values
+	default:
+		parts := make([]string, len(columns))
+		for i := range columns {
+			var part strings.Builder
+			for j := range i {
+				part.WriteString(columns[j] + " = " + bindMarker + " AND ")
+				seekArgs = append(seekArgs, values[j])
+			}
+			part.WriteString(columns[i] + " " + operators[i] + " " + bindMarker)
+			seekArgs = append(seekArgs, values[i])
+			parts[i] = part.String()
+			if i > 0 {
+				parts[i] = "(" + parts[i] + ")"
+			}
+		}
+		seekSQL = strings.Join(parts, " OR ")
+	}
+
+	p.seekCond = *newCondByValue(conjunctions[andTag], seekSQL, seekArgs)
+
+	if before {
+		p.orderBySQL = ""
+		p.SliceOrderByToSQL(reversed)
+	}
+
+	return p
+}
+
 func (p *QuerySetImpl) GetLimitSQL() string {
 	return p.limitSQL
 }
@@ -756,13 +972,11 @@ func (p *QuerySetImpl) SliceGroupByToSQL(groupBy []string) QuerySet {
 	}
 
 	var b strings.Builder
-	b.WriteString("`")
-	b.WriteString(strings.TrimSpace(groupBy[0]))
+	b.WriteString(p.quote(strings.TrimSpace(groupBy[0])))
 	for _, by := range groupBy[1:] {
-		b.WriteString("`, `")
-		b.WriteString(strings.TrimSpace(by))
+		b.WriteString(", ")
+		b.WriteString(p.quote(strings.TrimSpace(by)))
 	}
-	b.WriteString("`")
 
 	p.groupSQL = b.String()
 
@@ -784,3 +998,20 @@ func (p *QuerySetImpl) HavingToSQL(having string, args ...any) QuerySet {
 
 	return p
 }
+
+// GetJoinSQL returns the join clauses and the args of their conditions, which come before the args of WHERE.
+func (p *QuerySetImpl) GetJoinSQL() (string, []any) {
+	return p.joinSQL.SQL, p.joinSQL.Args
+}
+
+// JoinToSQL appends a join clause with the args of its condition,
+// the columns without a table are qualified by table afterward.
+func (p *QuerySetImpl) JoinToSQL(table, join string, args ...any) QuerySet {
+	p.setCalled(QsJoin)
+
+	p.qualifier = table
+	p.joinSQL.SQL += join
+	p.joinSQL.Args = append(p.joinSQL.Args, args...)
+
+	return p
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/leisurelicht/norm/internal/operator"
 	ch_go "github.com/leisurelicht/norm/operator/clickhouse/clickhouse-go"
 	go_zero "github.com/leisurelicht/norm/operator/mysql/go-zero"
+	pg_sqlx "github.com/leisurelicht/norm/operator/postgres/sqlx"
 )
 
 func TestFilter(t *testing.T) {
@@ -47,9 +48,7 @@ func TestFilter(t *testing.T) {
 		{"lt_cond", args{isFilter, []any{Cond{"test__lt": 1}}}, want{" WHERE (`test` < ?)", []any{1}}},
 		{"lte_cond", args{isFilter, []any{Cond{"test__lte": 1}}}, want{" WHERE (`test` <= ?)", []any{1}}},
 		{"len_cond", args{isFilter, []any{Cond{"test__len": 1}}}, want{" WHERE (LENGTH(`test`) = ?)", []any{1}}},
-		{"in_string_cond", args{isFilter, []any{Cond{"test__in": "1,2,3"}}}, want{" WHERE (`test` IN (1,2,3))", []any{}}},
 		{"in_list_cond", args{isFilter, []any{Cond{"test__in": []int{1, 2}}}}, want{" WHERE (`test` IN (?,?))", []any{1, 2}}},
-		{"not_in_string_cond", args{isFilter, []any{Cond{"test__not_in": "1,2,3"}}}, want{" WHERE (`test` NOT IN (1,2,3))", []any{}}},
 		{"not_list_in_cond", args{isFilter, []any{Cond{"test__not_in": []int{1, 2}}}}, want{" WHERE (`test` NOT IN (?,?))", []any{1, 2}}},
 		{"between_cond", args{isFilter, []any{Cond{"test__between": []int{1, 2}}}}, want{" WHERE (`test` BETWEEN ? AND ?)", []any{1, 2}}},
 		{"not_between_cond", args{isFilter, []any{Cond{"test__not_between": []int{1, 2}}}}, want{" WHERE (`test` NOT BETWEEN ? AND ?)", []any{1, 2}}},
@@ -117,7 +116,7 @@ func TestFilter(t *testing.T) {
 		{"exclude_cond", args{isFilter, []any{Cond{"test__exclude": []any{1, 2}}, AND{"test2__exclude": 3}, OR{"test3__exclude": []any{4, 5}}}}, want{" WHERE ((`test` != ? AND `test` != ?) AND (`test2` != ?) OR (`test3` != ? AND `test3` != ?))", []any{1, 2, 3, 4, 5}}},
 		{"iexact_cond", args{isFilter, []any{Cond{"test__iexact": []any{1, 2}}, AND{"test2__iexact": 3}, OR{"test3__iexact": []any{4, 5}}}}, want{" WHERE ((`test` LIKE ? AND `test` LIKE ?) AND (`test2` LIKE ?) OR (`test3` LIKE ? AND `test3` LIKE ?))", []any{1, 2, 3, 4, 5}}},
 		{"gt_cond", args{isFilter, []any{Cond{"test__gt": 1}, AND{"test2__gt": 2}, OR{"test3__gt": 3}}}, want{" WHERE ((`test` > ?) AND (`test2` > ?) OR (`test3` > ?))", []any{1, 2, 3}}},
-		{"in_cond", args{isFilter, []any{Cond{"test__in": "1,2,3"}, AND{"test2__in": []any{4, 5}}, OR{"test3__in": []any{6, 7}}}}, want{" WHERE ((`test` IN (1,2,3)) AND (`test2` IN (?,?)) OR (`test3` IN (?,?)))", []any{4, 5, 6, 7}}},
+		{"in_cond", args{isFilter, []any{Cond{"test__in": []any{1, 2, 3}}, AND{"test2__in": []any{4, 5}}, OR{"test3__in": []any{6, 7}}}}, want{" WHERE ((`test` IN (?,?,?)) AND (`test2` IN (?,?)) OR (`test3` IN (?,?)))", []any{1, 2, 3, 4, 5, 6, 7}}},
 
 		{"default_mix_contains_conj", args{isFilter, []any{Cond{"test": 1}, Cond{"test2__contains": []string{"e", "s"}}}}, want{" WHERE ((`test` = ?) AND (`test2` LIKE BINARY ? OR `test2` LIKE BINARY ?))", []any{1, "%e%", "%s%"}}},
 
@@ -327,6 +326,8 @@ func TestFilterError(t *testing.T) {
 		{"unsupported value21", args{isFilter, []any{Cond{"test__lte": [1]int{1}}}}, want{fmt.Errorf(unsupportedValueError, "lte", "array")}},
 		{"unsupported value22", args{isFilter, []any{Cond{"test__len": [1]int{1}}}}, want{fmt.Errorf(unsupportedValueError, "len", "array")}},
 		{"unsupported value23", args{isFilter, []any{Cond{"test__in": 1}}}, want{err: fmt.Errorf(unsupportedValueError, "in", "int")}},
+		{"unsupported value24", args{isFilter, []any{Cond{"test__in": "1,2,3"}}}, want{err: fmt.Errorf(inStringValueError, "in")}},
+		{"unsupported value24_1", args{isFilter, []any{Cond{"test__not_in": "1,2,3"}}}, want{err: fmt.Errorf(inStringValueError, "in")}},
 		{"unsupported value25", args{isFilter, []any{Cond{"test__between": "test"}}}, want{err: fmt.Errorf(unsupportedValueError, "between", "string")}},
 		{"unsupported value26", args{isFilter, []any{Cond{"test__contains": ""}}}, want{err: fmt.Errorf(unsupportedValueError, "contains", "blank string")}},
 		{"unsupported value27", args{isFilter, []any{Cond{"test__contains": true}}}, want{err: fmt.Errorf(unsupportedValueError, "contains", "bool")}},
@@ -635,46 +636,67 @@ func TestWhereError(t *testing.T) {
 	}
 }
 
-func TestFilterAndWhereConflict(t *testing.T) {
-	operator := go_zero.NewOperator(nil)
-	p := NewQuerySet(operator)
-
-	p.WhereToSQL("test = ?", 1)
-	p.FilterToSQL(NotNot, Cond{"test": 1})
-	if p.Error() == nil {
-		t.Error("Test Where Filter Conflict Not Occur Error")
-	} else if p.Error().Error() != fmt.Errorf(FilterOrWhereError, "Filter").Error() {
-		t.Errorf("TestFilterAndExcludeConflict not working as expected")
-	}
-
-	p = NewQuerySet(operator)
-	p.WhereToSQL("test = ?", 1)
-	p.FilterToSQL(IsNot, Cond{"test": 1})
-	if p.Error() == nil {
-		t.Error("Test Where Exclude Conflict Not Occur Error")
-	} else if p.Error().Error() != fmt.Errorf(FilterOrWhereError, "Exclude").Error() {
-		t.Errorf("TestFilterAndExcludeConflict not working as expected")
-	}
-
-	p = NewQuerySet(operator)
-	p.FilterToSQL(NotNot, Cond{"test": 1})
-	p.WhereToSQL("test = ?", 1)
-	if p.Error() == nil {
-		t.Error("Test Filter Where Conflict Not Occur Error")
-		return
-	} else if p.Error().Error() != fmt.Errorf(FilterOrWhereError, "Filter").Error() {
-		t.Errorf("TestFilterAndWhereConflict SQL Occur Error -> error:%+v", p.Error().Error())
-		t.Errorf("TestFilterAndWhereConflict SQL Occur Error -> want:%+v", fmt.Errorf(FilterOrWhereError, "Filter").Error())
+func TestFilterAndWhere(t *testing.T) {
+	tests := []struct {
+		name string
+		fn   func(p QuerySet)
+		sql  string
+		args []any
+	}{
+		{"where_filter", func(p QuerySet) {
+			p.WhereToSQL("test = ?", 1)
+			p.FilterToSQL(NotNot, Cond{"test2": 2})
+		}, " WHERE (test = ?) AND (`test2` = ?)", []any{1, 2}},
+		{"filter_where", func(p QuerySet) {
+			p.FilterToSQL(NotNot, Cond{"test": 1})
+			p.WhereToSQL("test2 = ?", 2)
+		}, " WHERE (`test` = ?) AND (test2 = ?)", []any{1, 2}},
+		{"exclude_where", func(p QuerySet) {
+			p.FilterToSQL(IsNot, Cond{"test": 1})
+			p.WhereToSQL("test2 = ?", 2)
+		}, " WHERE NOT (`test` = ?) AND (test2 = ?)", []any{1, 2}},
+		{"where_exclude", func(p QuerySet) {
+			p.WhereToSQL("test = ?", 1)
+			p.FilterToSQL(IsNot, Cond{"test2": 2})
+		}, " WHERE (test = ?) AND NOT (`test2` = ?)", []any{1, 2}},
+		{"or_where", func(p QuerySet) {
+			p.FilterToSQL(NotNot, Cond{"test": 1, "test2": 2, SortKey: []string{"test", "test2"}})
+			p.OrWhereToSQL("test3 = ? OR test4 = ?", 3, 4)
+			p.WhereToSQL("test5 = ?", 5)
+		}, " WHERE ((`test` = ? AND `test2` = ?) OR (test3 = ? OR test4 = ?)) AND (test5 = ?)", []any{1, 2, 3, 4, 5}},
+		{"or_where_filter", func(p QuerySet) {
+			p.WhereToSQL("a = ?", 1)
+			p.OrWhereToSQL("b = ?", 2)
+			p.FilterToSQL(NotNot, Cond{"c": 3})
+		}, " WHERE ((a = ?) OR (b = ?)) AND (`c` = ?)", []any{1, 2, 3}},
+		{"or_where_exclude_or_where", func(p QuerySet) {
+			p.WhereToSQL("a = ?", 1)
+			p.OrWhereToSQL("b = ?", 2)
+			p.FilterToSQL(IsNot, Cond{"c": 3})
+			p.OrWhereToSQL("d = ?", 4)
+			p.WhereToSQL("e = ?", 5)
+		}, " WHERE (((a = ?) OR (b = ?)) AND NOT (`c` = ?) OR (d = ?)) AND (e = ?)", []any{1, 2, 3, 4, 5}},
+		{"empty_where", func(p QuerySet) {
+			p.FilterToSQL(NotNot, Cond{"test": 1})
+			p.WhereToSQL("")
+		}, " WHERE (`test` = ?)", []any{1}},
 	}
 
-	p = NewQuerySet(operator)
-	p.FilterToSQL(IsNot, Cond{"test": 1})
-	p.WhereToSQL("test = ?", 1)
-	if p.Error() == nil {
-		t.Error("Test Exclude Where Conflict Not Occur Error")
-	} else if p.Error().Error() != fmt.Errorf(FilterOrWhereError, "Exclude").Error() {
-		t.Errorf("TestExcludeAndWhereConflict SQL Occur Error -> error:%+v", p.Error().Error())
-		t.Errorf("TestExcludeAndWhereConflict SQL Occur Error -> want:%+v", fmt.Errorf(FilterOrWhereError, "Exclude").Error())
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewQuerySet(go_zero.NewOperator(nil))
+			tt.fn(p)
+			if p.Error() != nil {
+				t.Fatalf("got error %v", p.Error())
+			}
+			sql, args := p.GetQuerySet()
+			if sql != tt.sql {
+				t.Errorf("got sql %q, want %q", sql, tt.sql)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("got args %v, want %v", args, tt.args)
+			}
+		})
 	}
 }
 
@@ -969,6 +991,100 @@ func TestOrderByError(t *testing.T) {
 	}
 }
 
+func TestSeek(t *testing.T) {
+	type args struct {
+		orderBy []string
+		values  []any
+		before  bool
+	}
+	type want struct {
+		sql   string
+		args  []any
+		order string
+	}
+	tests := []struct {
+		name string
+		args args
+		want want
+	}{
+		{"one_asc", args{[]string{"id"}, []any{1}, false}, want{" WHERE (`id` > ?)", []any{1}, " ORDER BY `id` ASC"}},
+		{"one_desc", args{[]string{"-id"}, []any{1}, false}, want{" WHERE (`id` < ?)", []any{1}, " ORDER BY `id` DESC"}},
+		{"two_asc", args{[]string{"type", "id"}, []any{1, 2}, false},
+			want{" WHERE ((`type`, `id`) > (?, ?))", []any{1, 2}, " ORDER BY `type` ASC, `id` ASC"}},
+		{"two_desc", args{[]string{"-type", "-id"}, []any{1, 2}, false},
+			want{" WHERE ((`type`, `id`) < (?, ?))", []any{1, 2}, " ORDER BY `type` DESC, `id` DESC"}},
+		{"two_mix", args{[]string{"type", "-id"}, []any{1, 2}, false},
+			want{" WHERE (`type` > ? OR (`type` = ? AND `id` < ?))", []any{1, 1, 2}, " ORDER BY `type` ASC, `id` DESC"}},
+		{"three_mix", args{[]string{"-name", "type", "id"}, []any{"a", 1, 2}, false},
+			want{" WHERE (`name` < ? OR (`name` = ? AND `type` > ?) OR (`name` = ? AND `type` = ? AND `id` > ?))",
+				[]any{"a", "a", 1, "a", 1, 2}, " ORDER BY `name` DESC, `type` ASC, `id` ASC"}},
+		{"one_before", args{[]string{"id"}, []any{1}, true}, want{" WHERE (`id` < ?)", []any{1}, " ORDER BY `id` DESC"}},
+		{"two_before", args{[]string{"-type", "-id"}, []any{1, 2}, true},
+			want{" WHERE ((`type`, `id`) > (?, ?))", []any{1, 2}, " ORDER BY `type` ASC, `id` ASC"}},
+		{"two_mix_before", args{[]string{"type", "-id"}, []any{1, 2}, true},
+			want{" WHERE (`type` < ? OR (`type` = ? AND `id` > ?))", []any{1, 1, 2}, " ORDER BY `type` DESC, `id` ASC"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewQuerySet(go_zero.NewOperator(nil))
+			p.SliceOrderByToSQL(tt.args.orderBy)
+			p.SeekToSQL(tt.args.orderBy, tt.args.values, tt.args.before)
+
+			if p.Error() != nil {
+				t.Errorf("TestSeek SQL Occur Error -> error:%+v", p.Error())
+			}
+
+			sql, args := p.GetQuerySet()
+			if sql != tt.want.sql || !reflect.DeepEqual(args, tt.want.args) {
+				t.Errorf("TestSeek SQL Gen Error -> sql: %v, args: %v", sql, args)
+				t.Errorf("TestSeek SQL Gen Error -> want: %v, args: %v", tt.want.sql, tt.want.args)
+			}
+			if order := p.GetOrderBySQL(); order != tt.want.order {
+				t.Errorf("TestSeek OrderBy Gen Error -> order: %v, want: %v", order, tt.want.order)
+			}
+		})
+	}
+
+	t.Run("with_filter", func(t *testing.T) {
+		p := NewQuerySet(go_zero.NewOperator(nil))
+		p.FilterToSQL(NotNot, Cond{"name": "a"})
+		p.SeekToSQL([]string{"id"}, []any{1}, false)
+		sql, args := p.GetQuerySet()
+		if sql != " WHERE (`name` = ?) AND (`id` > ?)" || !reflect.DeepEqual(args, []any{"a", 1}) {
+			t.Errorf("TestSeek SQL Gen Error -> sql: %v, args: %v", sql, args)
+		}
+	})
+
+	t.Run("with_or_where", func(t *testing.T) {
+		p := NewQuerySet(go_zero.NewOperator(nil))
+		p.WhereToSQL("type = ?", 1)
+		p.OrWhereToSQL("name = ?", "x")
+		p.SeekToSQL([]string{"id"}, []any{3}, false)
+		sql, args := p.GetQuerySet()
+		if sql != " WHERE ((type = ?) OR (name = ?)) AND (`id` > ?)" || !reflect.DeepEqual(args, []any{1, "x", 3}) {
+			t.Errorf("TestSeek SQL Gen Error -> sql: %v, args: %v", sql, args)
+		}
+	})
+
+	t.Run("or_where_after_seek", func(t *testing.T) {
+		p := NewQuerySet(go_zero.NewOperator(nil))
+		p.SeekToSQL([]string{"id"}, []any{3}, true)
+		p.WhereToSQL("type = ? OR name = ?", 1, "x")
+		sql, args := p.GetQuerySet()
+		if sql != " WHERE (type = ? OR name = ?) AND (`id` < ?)" || !reflect.DeepEqual(args, []any{1, "x", 3}) {
+			t.Errorf("TestSeek SQL Gen Error -> sql: %v, args: %v", sql, args)
+		}
+	})
+
+	t.Run("values_len_error", func(t *testing.T) {
+		p := NewQuerySet(go_zero.NewOperator(nil))
+		p.SeekToSQL([]string{"type", "id"}, []any{1}, false)
+		if p.Error() == nil || p.Error().Error() != seekValuesLenError {
+			t.Errorf("TestSeek Occur Error -> error: %+v", p.Error())
+		}
+	})
+}
+
 func TestGroupBy(t *testing.T) {
 	type args struct {
 		groupby any
@@ -1184,3 +1300,150 @@ func TestMultipleCallFlags(t *testing.T) {
 		t.Errorf("Multiple call flags were not set correctly")
 	}
 }
+
+// Test that identifiers and lookups follow the PostgreSQL dialect of the operator
+func TestPostgresDialect(t *testing.T) {
+	p := NewQuerySet(pg_sqlx.NewOperator(nil))
+
+	p.FilterToSQL(NotNot, Cond{SortKey: []string{"name", "id"}, "name__icontains": "a", "id__in": []int{1, 2}})
+	p.SliceSelectToSQL([]string{"id", "name"})
+	p.SliceOrderByToSQL([]string{"-id", "name"})
+	p.SliceGroupByToSQL([]string{"id", "name"})
+
+	if p.Error() != nil {
+		t.Fatalf("unexpected error: %v", p.Error())
+	}
+
+	sql, args := p.GetQuerySet()
+	if want := ` WHERE ("name" ILIKE ? AND "id" IN (?,?))`; sql != want {
+		t.Errorf("got sql %q, want %q", sql, want)
+	}
+	if !reflect.DeepEqual(args, []any{"%a%", 1, 2}) {
+		t.Errorf("got args %v", args)
+	}
+	if want := `"id", "name"`; p.GetSelectSQL() != want {
+		t.Errorf("got select %q, want %q", p.GetSelectSQL(), want)
+	}
+	if want := ` ORDER BY "id" DESC, "name" ASC`; p.GetOrderBySQL() != want {
+		t.Errorf("got order by %q, want %q", p.GetOrderBySQL(), want)
+	}
+	if want := ` GROUP BY "id", "name"`; p.GetGroupBySQL() != want {
+		t.Errorf("got group by %q, want %q", p.GetGroupBySQL(), want)
+	}
+}
+
+func TestJoin(t *testing.T) {
+	p := NewQuerySet(go_zero.NewOperator(nil))
+
+	p.JoinToSQL("property", " INNER JOIN `source` ON `property`.`source_id` = `source`.`id`")
+	p.FilterToSQL(NotNot, Cond{SortKey: []string{"source.name", "id"}, "source.name__contains": "A", "id__in": []int{1, 2}})
+	p.SliceSelectToSQL([]string{"id", "source.name"})
+	p.SliceOrderByToSQL([]string{"-source.id", "id"})
+	p.SliceGroupByToSQL([]string{"source.name"})
+
+	if p.Error() != nil {
+		t.Fatalf("unexpected error: %v", p.Error())
+	}
+
+	join, joinArgs := p.GetJoinSQL()
+	if want := " INNER JOIN `source` ON `property`.`source_id` = `source`.`id`"; join != want || joinArgs != nil {
+		t.Errorf("got join %q args %v, want %q", join, joinArgs, want)
+	}
+	sql, args := p.GetQuerySet()
+	if want := " WHERE (`source`.`name` LIKE BINARY ? AND `property`.`id` IN (?,?))"; sql != want {
+		t.Errorf("got sql %q, want %q", sql, want)
+	}
+	if !reflect.DeepEqual(args, []any{"%A%", 1, 2}) {
+		t.Errorf("got args %v", args)
+	}
+	if want := "`property`.`id`, `source`.`name`"; p.GetSelectSQL() != want {
+		t.Errorf("got select %q, want %q", p.GetSelectSQL(), want)
+	}
+	if want := " ORDER BY `source`.`id` DESC, `property`.`id` ASC"; p.GetOrderBySQL() != want {
+		t.Errorf("got order by %q, want %q", p.GetOrderBySQL(), want)
+	}
+	if want := " GROUP BY `source`.`name`"; p.GetGroupBySQL() != want {
+		t.Errorf("got group by %q, want %q", p.GetGroupBySQL(), want)
+	}
+
+	p.JoinToSQL("property", " LEFT JOIN `user` ON `property`.`user_id` = `user`.`id` AND `user`.`is_deleted`=?", false)
+	if join, joinArgs = p.GetJoinSQL(); !strings.HasSuffix(join, " AND `user`.`is_deleted`=?") || !reflect.DeepEqual(joinArgs, []any{false}) {
+		t.Errorf("got join %q args %v", join, joinArgs)
+	}
+
+	p.Reset()
+	p.FilterToSQL(NotNot, Cond{"id": 1})
+	if join, joinArgs = p.GetJoinSQL(); join != "" || joinArgs != nil {
+		t.Errorf("got join %q args %v after reset", join, joinArgs)
+	}
+	if sql, _ := p.GetQuerySet(); sql != " WHERE (`id` = ?)" {
+		t.Errorf("got sql %q after reset", sql)
+	}
+}
+
+type subquery struct {
+	sql  string
+	args []any
+	err  error
+}
+
+func (s subquery) QuerySQL() (string, []any, error) {
+	return s.sql, s.args, s.err
+}
+
+func (s subquery) ColumnSQL() (string, []any, error) {
+	return s.sql, s.args, s.err
+}
+
+func TestSubquery(t *testing.T) {
+	sub := subquery{sql: "SELECT `id` FROM `source` WHERE (`type` = ?)", args: []any{1}}
+	subErr := errors.New("sub error")
+
+	tests := []struct {
+		name    string
+		filter  Cond
+		wantSQL string
+		args    []any
+		err     error
+	}{
+		{"in", Cond{SortKey: []string{"name", "source_id"}, "name": "a", "source_id__in": sub},
+			" WHERE (`name` = ? AND `source_id` IN (SELECT `id` FROM `source` WHERE (`type` = ?)))", []any{"a", 1}, nil},
+		{"not_in", Cond{"source_id__not_in": sub},
+			" WHERE (`source_id` NOT IN (SELECT `id` FROM `source` WHERE (`type` = ?)))", []any{1}, nil},
+		{"exists", Cond{SortKey: []string{"source", "id"}, "source__exists": sub, "id": 2},
+			" WHERE (EXISTS (SELECT `id` FROM `source` WHERE (`type` = ?)) AND `id` = ?)", []any{1, 2}, nil},
+		{"not_exists", Cond{"source__not_exists": sub},
+			" WHERE (NOT EXISTS (SELECT `id` FROM `source` WHERE (`type` = ?)))", []any{1}, nil},
+		{"exists_value", Cond{"source__exists": 1}, "", nil, fmt.Errorf(unsupportedValueError, "exists", "int")},
+		{"in_not_column_subquery", Cond{"id__in": struct{ Subquery }{sub}}, "", nil, fmt.Errorf(unsupportedValueError, "in", "struct")},
+		{"subquery_error", Cond{"id__in": subquery{err: subErr}}, "", nil, fmt.Errorf(subqueryError, "in", subErr)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := NewQuerySet(go_zero.NewOperator(nil))
+			p.FilterToSQL(NotNot, tt.filter)
+			if tt.err != nil {
+				if p.Error() == nil || p.Error().Error() != tt.err.Error() {
+					t.Fatalf("got error %v, want %v", p.Error(), tt.err)
+				}
+				return
+			}
+			if p.Error() != nil {
+				t.Fatalf("unexpected error: %v", p.Error())
+			}
+			sql, args := p.GetQuerySet()
+			if sql != tt.wantSQL {
+				t.Errorf("got sql %q, want %q", sql, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(args, tt.args) {
+				t.Errorf("got args %v, want %v", args, tt.args)
+			}
+		})
+	}
+
+	p := NewQuerySet(go_zero.NewOperator(nil))
+	p.FilterToSQL(NotNot, Cond{"id__in": subquery{err: subErr}})
+	if !errors.Is(p.Error(), subErr) {
+		t.Errorf("got error %v, want wrapping %v", p.Error(), subErr)
+	}
+}
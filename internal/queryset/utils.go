@@ -58,10 +58,10 @@ func isListKind(kind reflect.Kind) bool {
 	return kind == reflect.Slice || kind == reflect.Array
 }
 
-func genStrListValueLikeSQL(p *QuerySetImpl, filterConditions map[string]*cond, fieldName string, valueOf reflect.Value, notFlag int, operator, valueFormat string) {
+func genStrListValueLikeSQL(p *QuerySetImpl, filterConditions map[string]*cond, fieldName, column string, valueOf reflect.Value, notFlag int, operator, valueFormat string) {
 	op := p.OperatorSQL(operator, "")
 
-	filterConditions[fieldName] = newCondByValue("", fmt.Sprintf(op, fieldName, not[notFlag]), []any{fmt.Sprintf(valueFormat, valueOf.Index(0).Interface())})
+	filterConditions[fieldName] = newCondByValue("", fmt.Sprintf(op, column, not[notFlag]), []any{fmt.Sprintf(valueFormat, valueOf.Index(0).Interface())})
 	for i := 1; i < valueOf.Len(); i++ {
 		if valueOf.Index(i).IsZero() {
 			p.SetError(operatorValueEmptyError, operator)
@@ -69,7 +69,7 @@ func genStrListValueLikeSQL(p *QuerySetImpl, filterConditions map[string]*cond,
 		}
 
 		// notFlag^1 toggles between 0(AND) and 1(OR): NOT uses OR, non-NOT uses AND
-		filterConditions[fieldName].SQL += fmt.Sprintf(" "+conjunctions[notFlag^1]+" "+op, fieldName, not[notFlag])
+		filterConditions[fieldName].SQL += fmt.Sprintf(" "+conjunctions[notFlag^1]+" "+op, column, not[notFlag])
 		filterConditions[fieldName].Args = append(filterConditions[fieldName].Args, fmt.Sprintf(valueFormat, valueOf.Index(i).Interface()))
 	}
 }
@@ -85,16 +85,3 @@ func joinSQL(filterSql *string, filterArgs *[]any, index int, condition *cond) {
 	}
 	*filterArgs = append(*filterArgs, condition.Args...)
 }
-
-// wrapWithBackticks wraps field name with backticks
-func wrapWithBackticks(str string) string {
-	if str == "" {
-		return str
-	}
-	// If already wrapped, don't modify (optimized check)
-	if len(str) > 1 && str[0] == '`' && str[len(str)-1] == '`' {
-		return str
-	}
-	// Wrap field with backticks
-	return "`" + str + "`"
-}
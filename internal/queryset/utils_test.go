@@ -461,7 +461,7 @@ func Test_genStrListValueLikeSQL(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			genStrListValueLikeSQL(tt.args.p, tt.args.filterConditions, tt.args.fieldName, tt.args.valueOf, tt.args.notFlag, tt.args.operator, tt.args.valueFormat)
+			genStrListValueLikeSQL(tt.args.p, tt.args.filterConditions, tt.args.fieldName, tt.args.fieldName, tt.args.valueOf, tt.args.notFlag, tt.args.operator, tt.args.valueFormat)
 
 			if tt.wantError != nil {
 				if tt.args.p.err == nil {
@@ -793,8 +793,8 @@ func Test_joinSQL(t *testing.T) {
 	}
 }
 
-// shared test/benchmark cases for wrapWithBackticks
-var wrapWithBackticksCases = []struct {
+// shared test/benchmark cases for quote
+var quoteCases = []struct {
 	name  string
 	input string
 	want  string
@@ -807,23 +807,25 @@ var wrapWithBackticksCases = []struct {
 		"`this_is_a_very_long_field_name_with_multiple_parts_and_segments`"},
 }
 
-func Test_wrapWithBackticks(t *testing.T) {
-	for _, tt := range wrapWithBackticksCases {
+func Test_quote(t *testing.T) {
+	p := &QuerySetImpl{Operator: go_zero.NewOperator(nil)}
+	for _, tt := range quoteCases {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := wrapWithBackticks(tt.input); got != tt.want {
-				t.Errorf("wrapWithBackticks() = %v, want %v", got, tt.want)
+			if got := p.quote(tt.input); got != tt.want {
+				t.Errorf("quote() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
-// BenchmarkWrapWithBackticks measures the cost of wrapping field names with backticks
+// BenchmarkQuote measures the cost of wrapping field names with backticks
 // under the same set of cases used in unit tests.
-func BenchmarkWrapWithBackticks(b *testing.B) {
-	for _, tc := range wrapWithBackticksCases {
+func BenchmarkQuote(b *testing.B) {
+	p := &QuerySetImpl{Operator: go_zero.NewOperator(nil)}
+	for _, tc := range quoteCases {
 		b.Run(tc.name, func(b *testing.B) {
 			for i := 0; i < b.N; i++ {
-				_ = wrapWithBackticks(tc.input)
+				_ = p.quote(tc.input)
 			}
 		})
 	}
@@ -1,11 +1,295 @@
 package clickhouse_go_2
 
 import (
+	"context"
 	"database/sql"
+	"errors"
+	"strings"
 
 	clickhouse "github.com/ClickHouse/clickhouse-go/v2"
+
+	"github.com/leisurelicht/norm/internal/logger"
+	"github.com/leisurelicht/norm/internal/operator"
+	ck "github.com/leisurelicht/norm/internal/operator/clickhouse"
+	"github.com/leisurelicht/norm/internal/operator/stdsql"
 )
 
 func OpenDB(opt *clickhouse.Options) *sql.DB {
 	return clickhouse.OpenDB(opt)
 }
+
+const dbTag = "ch"
+
+type OperatorImpl struct {
+	conn      *sql.DB
+	mutations ck.Mutations
+	operator.AddOptions
+}
+
+// NewOperator returns a ClickHouse operator over the database/sql interface of clickhouse-go.
+func NewOperator(conn *sql.DB, opts ...operator.AddFunc) OperatorImpl {
+	addOptions := operator.DefaultAddOptions(dbTag)
+	for _, opt := range opts {
+		opt(&addOptions)
+	}
+	return OperatorImpl{
+		conn:       conn,
+		mutations:  ck.NewMutations(),
+		AddOptions: addOptions,
+	}
+}
+
+func WithTableName(tableName string) operator.AddFunc {
+	return operator.WithTableName(tableName)
+}
+
+// WithPlaceholder sets the placeholder the "?" bind markers are rebound to, e.g. "$" gives the "$1, $2, ..." numeric binds of clickhouse-go.
+func WithPlaceholder(placeholder string) operator.AddFunc {
+	return operator.WithPlaceholder(placeholder)
+}
+
+// LightweightDelete chooses how Remove deletes rows. It is enabled by default and uses
+// "DELETE FROM ... WHERE", otherwise the "ALTER TABLE ... DELETE WHERE" mutation is used.
+func (d OperatorImpl) LightweightDelete(enabled bool) OperatorImpl {
+	d.mutations.Lightweight = enabled
+	return d
+}
+
+// MutationsSync sets the mutations_sync level of the ALTER TABLE mutations run by Update and Remove.
+// 0 returns at once (default), 1 waits for the current replica and 2 waits for all replicas.
+func (d OperatorImpl) MutationsSync(level int) OperatorImpl {
+	d.mutations.Sync = level
+	return d
+}
+
+func (d OperatorImpl) SetTableName(tableName string) operator.Operator {
+	if d.TableName == "" {
+		d.TableName = tableName
+	}
+	return d
+}
+
+// SetPrimaryKey is a no-op, ClickHouse has no auto increment.
+func (d OperatorImpl) SetPrimaryKey(column string) operator.Operator {
+	return d
+}
+
+// ReportsAffectedRows reports false, Update and Remove return 0 as ClickHouse does not report affected rows.
+func (d OperatorImpl) ReportsAffectedRows() bool {
+	return false
+}
+
+// WithSession is a no-op, ClickHouse has no transactions.
+func (d OperatorImpl) WithSession(session any) operator.Operator {
+	return d
+}
+
+func (d OperatorImpl) GetTableName() string {
+	return d.TableName
+}
+
+func (d OperatorImpl) GetPlaceholder() string {
+	return d.Placeholder
+}
+
+func (d OperatorImpl) GetQuote() string {
+	return d.Quote
+}
+
+func (d OperatorImpl) GetDBTag() string {
+	return d.DBTag
+}
+
+func (d OperatorImpl) OperatorSQL(operator, method string) string {
+	op, ok := ck.Operators[operator]
+	if !ok {
+		return ""
+	}
+	if method == "" {
+		return op
+	}
+	if methodSQL, ok := ck.Methods[method]; ok {
+		op = strings.ReplaceAll(op, "?", methodSQL)
+	}
+	return op
+}
+
+// LockSQL returns no clause, ClickHouse has no row locks.
+func (d OperatorImpl) LockSQL(lock string) string {
+	return ""
+}
+
+// InsertSQL returns no template, ClickHouse has no unique keys to ignore or replace on.
+func (d OperatorImpl) InsertSQL(mode string) string {
+	return ""
+}
+
+// UpsertSQL returns no clause, ClickHouse has no unique keys to conflict on.
+func (d OperatorImpl) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return ""
+}
+
+// batch sends rows in one block. The driver only supports batches as a prepared
+// statement inside a transaction, the rows are sent on commit.
+func (d OperatorImpl) batch(ctx context.Context, query string, rows [][]any) (err error) {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			_ = tx.Rollback()
+		}
+	}()
+
+	stmt, err := tx.PrepareContext(ctx, d.Rebind(query))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = stmt.Close() }()
+
+	for _, row := range rows {
+		if _, err = stmt.ExecContext(ctx, row...); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit()
+}
+
+// Insert always returns 0 as id, ClickHouse has no auto increment.
+func (d OperatorImpl) Insert(ctx context.Context, query string, args ...any) (id int64, err error) {
+	if err = d.batch(ctx, query, [][]any{args}); err != nil {
+		logger.Errorf("Insert error: %s", err)
+		return 0, err
+	}
+
+	return 0, nil
+}
+
+func (d OperatorImpl) BulkInsert(ctx context.Context, query string, args []string, data []map[string]any) (num int64, err error) {
+	rows := make([][]any, 0, len(data))
+	for _, row := range data {
+		rows = append(rows, stdsql.BulkValues(args, []map[string]any{row}))
+	}
+
+	if err = d.batch(ctx, query, rows); err != nil {
+		logger.Errorf("BulkInsert error: %s", err)
+		return 0, err
+	}
+
+	return int64(len(rows)), nil
+}
+
+// Remove runs a lightweight delete or an ALTER TABLE ... DELETE mutation.
+// ClickHouse does not report affected rows, so the returned number is always 0.
+func (d OperatorImpl) Remove(ctx context.Context, query string, args ...any) (num int64, err error) {
+	if num, err = d.mutations.Remove(ctx, d.exec, query, args...); err != nil {
+		logger.Errorf("Remove error: %s", err)
+	}
+	return num, err
+}
+
+// Update runs an ALTER TABLE ... UPDATE mutation.
+// ClickHouse does not report affected rows, so the returned number is always 0.
+func (d OperatorImpl) Update(ctx context.Context, query string, args ...any) (num int64, err error) {
+	if num, err = d.mutations.Update(ctx, d.exec, query, args...); err != nil {
+		logger.Errorf("Update error: %s", err)
+	}
+	return num, err
+}
+
+// exec runs a mutation, the query is rebound to the placeholder first.
+func (d OperatorImpl) exec(ctx context.Context, query string, args ...any) error {
+	_, err := d.conn.ExecContext(ctx, d.Rebind(query), args...)
+	return err
+}
+
+func (d OperatorImpl) Count(ctx context.Context, condition string, args ...any) (num int64, err error) {
+	return d.count(ctx, "count()", condition, args...)
+}
+
+// CountDistinct counts the distinct values of the quoted column with uniqExact, the exact
+// implementation which count(DISTINCT) also uses by default.
+func (d OperatorImpl) CountDistinct(ctx context.Context, column, condition string, args ...any) (num int64, err error) {
+	return d.count(ctx, "uniqExact("+column+")", condition, args...)
+}
+
+func (d OperatorImpl) count(ctx context.Context, expr, condition string, args ...any) (num int64, err error) {
+	query := "SELECT " + expr + " FROM " + d.TableName + condition
+
+	err = d.conn.QueryRowContext(ctx, d.Rebind(query), args...).Scan(&num)
+
+	switch {
+	case err == nil:
+		return num, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, nil
+	default:
+		logger.Errorf("Count error: %s", err)
+		return 0, err
+	}
+}
+
+func (d OperatorImpl) Exist(ctx context.Context, condition string, args ...any) (bool, error) {
+	query := "SELECT count() FROM " + d.TableName + condition
+
+	var num int64
+	err := d.conn.QueryRowContext(ctx, d.Rebind(query), args...).Scan(&num)
+
+	switch {
+	case err == nil:
+		return num > 0, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	default:
+		logger.Errorf("Exist error: %s", err)
+		return false, err
+	}
+}
+
+func (d OperatorImpl) FindOne(ctx context.Context, model any, query string, args ...any) (err error) {
+	rows, err := d.conn.QueryContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("FindOne error: %s", err)
+		return err
+	}
+
+	err = stdsql.ScanRow(rows, model, d.DBTag)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, operator.ErrNotFound):
+		return operator.ErrNotFound
+	default:
+		logger.Errorf("FindOne error: %s", err)
+		return err
+	}
+}
+
+func (d OperatorImpl) FindAll(ctx context.Context, model any, query string, args ...any) (err error) {
+	rows, err := d.conn.QueryContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("FindAll error: %s", err)
+		return err
+	}
+
+	if err = stdsql.ScanRows(rows, model, d.DBTag); err != nil {
+		logger.Errorf("FindAll error: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindEach streams the rows into model, a pointer to struct, one by one and calls fn after each row.
+func (d OperatorImpl) FindEach(ctx context.Context, model any, fn func() error, query string, args ...any) (err error) {
+	rows, err := d.conn.QueryContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("FindEach error: %s", err)
+		return err
+	}
+
+	return stdsql.ScanEach(rows, model, d.DBTag, fn)
+}
@@ -0,0 +1,257 @@
+package clickhouse_go_2
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/leisurelicht/norm/internal/operator"
+)
+
+// fakeDB logs the statements run through its connections and returns its rows for every query.
+type fakeDB struct {
+	log     []string
+	columns []string
+	values  [][]driver.Value
+	execErr error
+}
+
+type fakeConn struct{ db *fakeDB }
+
+type fakeStmt struct {
+	db    *fakeDB
+	query string
+}
+
+type fakeTx struct{ db *fakeDB }
+
+type fakeRows struct {
+	columns []string
+	values  [][]driver.Value
+	pos     int
+}
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.db.log = append(c.db.log, "prepare "+query)
+	return fakeStmt{c.db, query}, nil
+}
+func (c fakeConn) Close() error { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) {
+	c.db.log = append(c.db.log, "begin")
+	return fakeTx(c), nil
+}
+
+func (tx fakeTx) Commit() error {
+	tx.db.log = append(tx.db.log, "commit")
+	return nil
+}
+func (tx fakeTx) Rollback() error {
+	tx.db.log = append(tx.db.log, "rollback")
+	return nil
+}
+
+func (s fakeStmt) Close() error  { return nil }
+func (s fakeStmt) NumInput() int { return -1 }
+func (s fakeStmt) Exec(args []driver.Value) (driver.Result, error) {
+	if s.db.execErr != nil {
+		return nil, s.db.execErr
+	}
+	s.db.log = append(s.db.log, fmt.Sprint("exec ", args))
+	return driver.RowsAffected(0), nil
+}
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{columns: s.db.columns, values: s.db.values}, nil
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.pos])
+	r.pos++
+	return nil
+}
+
+func openFake(t *testing.T, fake *fakeDB) *sql.DB {
+	db := sql.OpenDB(fake)
+	t.Cleanup(func() { _ = db.Close() })
+	return db
+}
+
+func TestNewOperator(t *testing.T) {
+	op := NewOperator(nil)
+	if op.GetDBTag() != "ch" {
+		t.Errorf("got db tag %q, want %q", op.GetDBTag(), "ch")
+	}
+	if op.GetQuote() != "`" {
+		t.Errorf("got quote %q, want %q", op.GetQuote(), "`")
+	}
+	if !op.mutations.Lightweight || op.mutations.Sync != 0 {
+		t.Errorf("got lightweightDelete %t mutationsSync %d, want true 0", op.mutations.Lightweight, op.mutations.Sync)
+	}
+
+	op = op.LightweightDelete(false).MutationsSync(2)
+	if op.mutations.Lightweight || op.mutations.Sync != 2 {
+		t.Errorf("got lightweightDelete %t mutationsSync %d, want false 2", op.mutations.Lightweight, op.mutations.Sync)
+	}
+
+	named := NewOperator(nil, WithTableName("events"))
+	if got := named.SetTableName("`other`").GetTableName(); got != "events" {
+		t.Errorf("got table name %q, want %q", got, "events")
+	}
+}
+
+func TestOperatorSQL(t *testing.T) {
+	op := NewOperator(nil)
+	tests := []struct {
+		operator string
+		method   string
+		want     string
+	}{
+		{"exact", "", "`%s` = ?"},
+		{"icontains", "", "`%s`%s ilike ?"},
+		{"exact", "toDate", "`%s` = toDate(?)"},
+		{"unknown", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operator+tt.method, func(t *testing.T) {
+			if got := op.OperatorSQL(tt.operator, tt.method); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockSQL(t *testing.T) {
+	op := NewOperator(nil)
+	for _, lock := range []string{"for_update", "for_share", "skip_locked", "nowait"} {
+		if got := op.LockSQL(lock); got != "" {
+			t.Errorf("got %q for %s, want empty", got, lock)
+		}
+	}
+}
+
+func TestBatch(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("BulkInsert", func(t *testing.T) {
+		fake := &fakeDB{}
+		num, err := NewOperator(openFake(t, fake)).BulkInsert(ctx, "INSERT INTO `events` (`id`,`name`) VALUES (?,?)",
+			[]string{"id", "name"}, []map[string]any{{"id": 1, "name": "a"}, {"id": 2, "name": "b"}})
+		if err != nil {
+			t.Fatalf("BulkInsert error: %v", err)
+		}
+		if num != 2 {
+			t.Errorf("got %d, want 2", num)
+		}
+		want := []string{"begin", "prepare INSERT INTO `events` (`id`,`name`) VALUES (?,?)", "exec [1 a]", "exec [2 b]", "commit"}
+		if !reflect.DeepEqual(fake.log, want) {
+			t.Errorf("got %q, want %q", fake.log, want)
+		}
+	})
+
+	t.Run("Insert with placeholder", func(t *testing.T) {
+		fake := &fakeDB{}
+		if _, err := NewOperator(openFake(t, fake), WithPlaceholder("$")).Insert(ctx, "INSERT INTO `events` (`id`,`name`) VALUES (?,?)", 1, "a"); err != nil {
+			t.Fatalf("Insert error: %v", err)
+		}
+		want := []string{"begin", "prepare INSERT INTO `events` (`id`,`name`) VALUES ($1,$2)", "exec [1 a]", "commit"}
+		if !reflect.DeepEqual(fake.log, want) {
+			t.Errorf("got %q, want %q", fake.log, want)
+		}
+	})
+
+	t.Run("Insert error", func(t *testing.T) {
+		fake := &fakeDB{execErr: errors.New("exec error")}
+		if _, err := NewOperator(openFake(t, fake)).Insert(ctx, "INSERT INTO `events` (`id`) VALUES (?)", 1); !errors.Is(err, fake.execErr) {
+			t.Fatalf("got error %v, want %v", err, fake.execErr)
+		}
+		want := []string{"begin", "prepare INSERT INTO `events` (`id`) VALUES (?)", "rollback"}
+		if !reflect.DeepEqual(fake.log, want) {
+			t.Errorf("got %q, want %q", fake.log, want)
+		}
+	})
+}
+
+type event struct {
+	ID   int64  `ch:"id"`
+	Name string `ch:"name" db:"title"`
+	Note string `ch:"-"`
+}
+
+func TestFind(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeDB{
+		columns: []string{"id", "name"},
+		values:  [][]driver.Value{{int64(1), "a"}, {int64(2), "b"}},
+	}
+	op := NewOperator(openFake(t, fake))
+
+	var got []event
+	if err := op.FindAll(ctx, &got, "SELECT `id`,`name` FROM `events`"); err != nil {
+		t.Fatalf("FindAll error: %v", err)
+	}
+	if want := []event{{ID: 1, Name: "a"}, {ID: 2, Name: "b"}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	var one event
+	if err := op.FindOne(ctx, &one, "SELECT `id`,`name` FROM `events` LIMIT 1"); err != nil {
+		t.Fatalf("FindOne error: %v", err)
+	}
+	if one != (event{ID: 1, Name: "a"}) {
+		t.Errorf("got %+v", one)
+	}
+
+	var each []string
+	err := op.FindEach(ctx, &one, func() error {
+		each = append(each, one.Name)
+		return nil
+	}, "SELECT `id`,`name` FROM `events`")
+	if err != nil {
+		t.Fatalf("FindEach error: %v", err)
+	}
+	if !reflect.DeepEqual(each, []string{"a", "b"}) {
+		t.Errorf("got %v", each)
+	}
+
+	fake.values = nil
+	if err := op.FindOne(ctx, &one, "SELECT `id`,`name` FROM `events` LIMIT 1"); !errors.Is(err, operator.ErrNotFound) {
+		t.Errorf("got error %v, want %v", err, operator.ErrNotFound)
+	}
+}
+
+func TestMutation(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeDB{}
+	op := NewOperator(openFake(t, fake)).LightweightDelete(false).MutationsSync(1)
+
+	num, err := op.Update(ctx, "UPDATE `events` SET `name`=? WHERE (`id` = ?)", "c", 1)
+	if err != nil {
+		t.Fatalf("Update error: %v", err)
+	}
+	if num != 0 {
+		t.Errorf("got %d, want 0", num)
+	}
+	if _, err = op.Remove(ctx, "DELETE FROM `events` WHERE (`id` = ?)", 1); err != nil {
+		t.Fatalf("Remove error: %v", err)
+	}
+
+	want := []string{
+		"prepare ALTER TABLE `events` UPDATE `name`=? WHERE (`id` = ?) SETTINGS mutations_sync = 1", "exec [c 1]",
+		"prepare ALTER TABLE `events` DELETE WHERE (`id` = ?) SETTINGS mutations_sync = 1", "exec [1]",
+	}
+	if !reflect.DeepEqual(fake.log, want) {
+		t.Errorf("got %q, want %q", fake.log, want)
+	}
+}
@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"errors"
-	"fmt"
 	"log"
 	"reflect"
 	"strings"
@@ -29,7 +28,8 @@ func Open(opt *clickhouse.Options) (driver.Conn, error) {
 const dbTag = "ch"
 
 type OperatorImpl struct {
-	conn driver.Conn
+	conn      driver.Conn
+	mutations ck.Mutations
 	operator.AddOptions
 }
 
@@ -40,10 +40,25 @@ func NewOperator(conn driver.Conn, opts ...operator.AddFunc) OperatorImpl {
 	}
 	return OperatorImpl{
 		conn:       conn,
+		mutations:  ck.NewMutations(),
 		AddOptions: addOptions,
 	}
 }
 
+// LightweightDelete chooses how Remove deletes rows. It is enabled by default and uses
+// "DELETE FROM ... WHERE", otherwise the "ALTER TABLE ... DELETE WHERE" mutation is used.
+func (d OperatorImpl) LightweightDelete(enabled bool) OperatorImpl {
+	d.mutations.Lightweight = enabled
+	return d
+}
+
+// MutationsSync sets the mutations_sync level of the ALTER TABLE mutations run by Update and Remove.
+// 0 returns at once (default), 1 waits for the current replica and 2 waits for all replicas.
+func (d OperatorImpl) MutationsSync(level int) OperatorImpl {
+	d.mutations.Sync = level
+	return d
+}
+
 func (d OperatorImpl) SetTableName(tableName string) operator.Operator {
 	if d.TableName == "" {
 		d.TableName = tableName
@@ -51,6 +66,16 @@ func (d OperatorImpl) SetTableName(tableName string) operator.Operator {
 	return d
 }
 
+// SetPrimaryKey is a no-op, ClickHouse has no auto increment.
+func (d OperatorImpl) SetPrimaryKey(column string) operator.Operator {
+	return d
+}
+
+// ReportsAffectedRows reports false, Update and Remove return 0 as ClickHouse does not report affected rows.
+func (d OperatorImpl) ReportsAffectedRows() bool {
+	return false
+}
+
 func (d OperatorImpl) WithSession(session any) operator.Operator {
 	return d
 }
@@ -63,6 +88,10 @@ func (d OperatorImpl) GetPlaceholder() string {
 	return d.Placeholder
 }
 
+func (d OperatorImpl) GetQuote() string {
+	return d.Quote
+}
+
 func (d OperatorImpl) GetDBTag() string {
 	return d.DBTag
 }
@@ -81,8 +110,23 @@ func (d OperatorImpl) OperatorSQL(operator, method string) string {
 	return op
 }
 
+// LockSQL returns no clause, ClickHouse has no row locks.
+func (d OperatorImpl) LockSQL(lock string) string {
+	return ""
+}
+
+// InsertSQL returns no template, ClickHouse has no unique keys to ignore or replace on.
+func (d OperatorImpl) InsertSQL(mode string) string {
+	return ""
+}
+
+// UpsertSQL returns no clause, ClickHouse has no unique keys to conflict on.
+func (d OperatorImpl) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return ""
+}
+
 func (d OperatorImpl) Insert(ctx context.Context, query string, args ...any) (id int64, err error) {
-	err = d.conn.AsyncInsert(ctx, query, true, args...)
+	err = d.conn.AsyncInsert(ctx, d.Rebind(query), true, args...)
 	if err != nil {
 		return 0, err
 	}
@@ -91,7 +135,7 @@ func (d OperatorImpl) Insert(ctx context.Context, query string, args ...any) (id
 }
 
 func (d OperatorImpl) BulkInsert(ctx context.Context, query string, args []string, data []map[string]any) (num int64, err error) {
-	batch, err := d.conn.PrepareBatch(ctx, query)
+	batch, err := d.conn.PrepareBatch(ctx, d.Rebind(query))
 	if err != nil {
 		return 0, err
 	}
@@ -120,18 +164,43 @@ func (d OperatorImpl) BulkInsert(ctx context.Context, query string, args []strin
 	return num, nil
 }
 
+// Remove runs a lightweight delete or an ALTER TABLE ... DELETE mutation.
+// ClickHouse does not report affected rows, so the returned number is always 0.
 func (d OperatorImpl) Remove(ctx context.Context, query string, args ...any) (num int64, err error) {
-	return 0, fmt.Errorf("remove not implemented for clickhouse")
+	if num, err = d.mutations.Remove(ctx, d.exec, query, args...); err != nil {
+		logger.Errorf("Remove error: %s", err)
+	}
+	return num, err
 }
 
+// Update runs an ALTER TABLE ... UPDATE mutation.
+// ClickHouse does not report affected rows, so the returned number is always 0.
 func (d OperatorImpl) Update(ctx context.Context, query string, args ...any) (num int64, err error) {
-	return 0, fmt.Errorf("update not implemented for clickhouse")
+	if num, err = d.mutations.Update(ctx, d.exec, query, args...); err != nil {
+		logger.Errorf("Update error: %s", err)
+	}
+	return num, err
+}
+
+// exec runs a mutation, the query is rebound to the placeholder first.
+func (d OperatorImpl) exec(ctx context.Context, query string, args ...any) error {
+	return d.conn.Exec(ctx, d.Rebind(query), args...)
 }
 
 func (d OperatorImpl) Count(ctx context.Context, condition string, args ...any) (num int64, err error) {
-	query := "SELECT count() FROM " + d.TableName + condition
+	return d.count(ctx, "count()", condition, args...)
+}
+
+// CountDistinct counts the distinct values of the quoted column with uniqExact, the exact
+// implementation which count(DISTINCT) also uses by default.
+func (d OperatorImpl) CountDistinct(ctx context.Context, column, condition string, args ...any) (num int64, err error) {
+	return d.count(ctx, "uniqExact("+column+")", condition, args...)
+}
+
+func (d OperatorImpl) count(ctx context.Context, expr, condition string, args ...any) (num int64, err error) {
+	query := "SELECT " + expr + " FROM " + d.TableName + condition
 
-	err = d.conn.QueryRow(ctx, query, args...).Scan(&num)
+	err = d.conn.QueryRow(ctx, d.Rebind(query), args...).Scan(&num)
 
 	switch {
 	case err == nil:
@@ -148,7 +217,7 @@ func (d OperatorImpl) Exist(ctx context.Context, condition string, args ...any)
 	query := "SELECT count() FROM " + d.TableName + condition
 
 	var num int64
-	err := d.conn.QueryRow(ctx, query, args...).Scan(&num)
+	err := d.conn.QueryRow(ctx, d.Rebind(query), args...).Scan(&num)
 
 	switch {
 	case err == nil:
@@ -162,7 +231,7 @@ func (d OperatorImpl) Exist(ctx context.Context, condition string, args ...any)
 }
 
 func (d OperatorImpl) FindOne(ctx context.Context, model any, query string, args ...any) (err error) {
-	err = d.conn.QueryRow(ctx, query, args...).ScanStruct(model)
+	err = d.conn.QueryRow(ctx, d.Rebind(query), args...).ScanStruct(model)
 
 	switch {
 	case err == nil:
@@ -177,7 +246,7 @@ func (d OperatorImpl) FindOne(ctx context.Context, model any, query string, args
 }
 
 func (d OperatorImpl) FindAll(ctx context.Context, model any, query string, args ...any) (err error) {
-	rows, err := d.conn.Query(ctx, query, args...)
+	rows, err := d.conn.Query(ctx, d.Rebind(query), args...)
 	if err != nil {
 		return err
 	}
@@ -200,3 +269,26 @@ func (d OperatorImpl) FindAll(ctx context.Context, model any, query string, args
 
 	return nil
 }
+
+// FindEach streams the rows into model, a pointer to struct, one by one and calls fn after each row.
+func (d OperatorImpl) FindEach(ctx context.Context, model any, fn func() error, query string, args ...any) (err error) {
+	rows, err := d.conn.Query(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("FindEach error: %s", err)
+		return err
+	}
+
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		if err = rows.ScanStruct(model); err != nil {
+			logger.Errorf("FindEach scan struct failed. error: %s", err)
+			return err
+		}
+		if err = fn(); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
@@ -3,7 +3,6 @@ package go_zero
 import (
 	"context"
 	"errors"
-	"fmt"
 	"strings"
 
 	mysqlDriver "github.com/go-sql-driver/mysql"
@@ -12,6 +11,7 @@ import (
 
 	"github.com/leisurelicht/norm/internal/operator"
 	mysqlOp "github.com/leisurelicht/norm/internal/operator/mysql"
+	"github.com/leisurelicht/norm/internal/operator/stdsql"
 )
 
 // NewMysql returns a mysql connection.
@@ -21,8 +21,16 @@ func NewMysql(datasource string, opts ...sqlx.SqlOption) sqlx.SqlConn {
 
 const dbTag = "db"
 
+// ErrSessionNotStreamable is returned by FindEach when the session of WithSession is not a database/sql
+// transaction, the rows are not streamed from the pool then, as they would be read outside of the session.
+var ErrSessionNotStreamable = errors.New("session has no database/sql transaction to stream rows from")
+
 type OperatorImpl struct {
 	conn sqlx.SqlConn
+	// raw is the database/sql transaction of the session, FindEach streams rows through it.
+	raw stdsql.Conn
+	// session reports whether WithSession is called, FindEach must not stream outside of it then.
+	session bool
 	operator.AddOptions
 }
 
@@ -48,6 +56,16 @@ func (d OperatorImpl) SetTableName(tableName string) operator.Operator {
 	return d
 }
 
+// SetPrimaryKey is a no-op, the id of Insert is the LastInsertId of the driver.
+func (d OperatorImpl) SetPrimaryKey(column string) operator.Operator {
+	return d
+}
+
+// ReportsAffectedRows reports true, Update and Remove return the affected rows of the driver.
+func (d OperatorImpl) ReportsAffectedRows() bool {
+	return true
+}
+
 func (d OperatorImpl) GetTableName() string {
 	return d.TableName
 }
@@ -56,6 +74,10 @@ func (d OperatorImpl) GetPlaceholder() string {
 	return d.Placeholder
 }
 
+func (d OperatorImpl) GetQuote() string {
+	return d.Quote
+}
+
 func (d OperatorImpl) GetDBTag() string {
 	return d.DBTag
 }
@@ -63,6 +85,8 @@ func (d OperatorImpl) GetDBTag() string {
 func (d OperatorImpl) WithSession(session any) operator.Operator {
 	if sqlSession, ok := session.(sqlx.Session); ok {
 		d.conn = sqlx.NewSqlConnFromSession(sqlSession)
+		d.raw, _ = session.(stdsql.Conn)
+		d.session = true
 	}
 	return d
 }
@@ -81,8 +105,23 @@ func (d OperatorImpl) OperatorSQL(operator, method string) string {
 	return op
 }
 
+// LockSQL returns the row locking clause of the lock, e.g. " FOR UPDATE".
+func (d OperatorImpl) LockSQL(lock string) string {
+	return mysqlOp.Locks[lock]
+}
+
+// InsertSQL returns the insert template of the mode, e.g. "INSERT IGNORE INTO %s (%s) VALUES (%s)".
+func (d OperatorImpl) InsertSQL(mode string) string {
+	return mysqlOp.Inserts[mode]
+}
+
+// UpsertSQL returns the ON DUPLICATE KEY UPDATE clause, MySQL finds the conflict by the unique keys itself.
+func (d OperatorImpl) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return operator.OnDuplicateKeyUpdate(updateColumns)
+}
+
 func (d OperatorImpl) Insert(ctx context.Context, query string, args ...any) (id int64, err error) {
-	res, err := d.conn.ExecCtx(ctx, query, args...)
+	res, err := d.conn.ExecCtx(ctx, d.Rebind(query), args...)
 	if err != nil {
 		if isDuplicateKeyError(err) {
 			return 0, operator.ErrDuplicateKey
@@ -100,7 +139,7 @@ func (d OperatorImpl) Insert(ctx context.Context, query string, args ...any) (id
 }
 
 func (d OperatorImpl) BulkInsert(ctx context.Context, query string, args []string, data []map[string]any) (num int64, err error) {
-	query, err = buildBulkInsertQuery(query, len(data))
+	query, err = operator.BuildBulkInsertQuery(query, len(data))
 	if err != nil {
 		logc.Errorf(ctx, "Build bulk insert query error: %s", err)
 		return 0, err
@@ -117,7 +156,7 @@ func (d OperatorImpl) BulkInsert(ctx context.Context, query string, args []strin
 		}
 	}
 
-	result, err := d.conn.ExecCtx(ctx, query, values...)
+	result, err := d.conn.ExecCtx(ctx, d.Rebind(query), values...)
 	if err != nil {
 		if isDuplicateKeyError(err) {
 			return 0, operator.ErrDuplicateKey
@@ -136,59 +175,13 @@ func (d OperatorImpl) BulkInsert(ctx context.Context, query string, args []strin
 	return num, err
 }
 
-func buildBulkInsertQuery(query string, rows int) (string, error) {
-	if rows <= 1 {
-		return query, nil
-	}
-
-	lower := strings.ToLower(query)
-
-	// Limit VALUES keyword search to the main INSERT ... VALUES clause.
-	// If the query contains an "ON DUPLICATE KEY" section, ignore everything after it
-	// so we don't accidentally match the MySQL VALUES() function there.
-	searchEnd := strings.Index(lower, " on duplicate key")
-	searchLower := lower
-	if searchEnd > 0 {
-		searchLower = lower[:searchEnd]
-	}
-
-	// Use the last occurrence so identifiers like `values_log` or `default_values`
-	// in table/column names don't confuse us.
-	pos := strings.LastIndex(searchLower, "values")
-	if pos < 0 {
-		return "", fmt.Errorf("invalid insert query, missing VALUES: %q", query)
-	}
-
-	head := strings.TrimSpace(query[:pos+len("values")])
-	tail := strings.TrimSpace(query[pos+len("values"):])
-	left := strings.IndexByte(tail, '(')
-	right := strings.IndexByte(tail, ')')
-	if left < 0 || right <= left {
-		return "", fmt.Errorf("invalid insert query, bad values placeholder: %q", query)
-	}
-
-	rowTemplate := tail[left : right+1]
-	suffix := strings.TrimSpace(tail[right+1:])
-
-	valueTemplates := make([]string, rows)
-	for i := range valueTemplates {
-		valueTemplates[i] = rowTemplate
-	}
-
-	bulkQuery := head + " " + strings.Join(valueTemplates, ",")
-	if suffix != "" {
-		bulkQuery += " " + suffix
-	}
-	return bulkQuery, nil
-}
-
 func isDuplicateKeyError(err error) bool {
 	var mysqlErr *mysqlDriver.MySQLError
 	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
 }
 
 func (d OperatorImpl) Remove(ctx context.Context, query string, args ...any) (num int64, err error) {
-	res, err := d.conn.ExecCtx(ctx, query, args...)
+	res, err := d.conn.ExecCtx(ctx, d.Rebind(query), args...)
 	if err != nil {
 		logc.Errorf(ctx, "Remove error: %s", err)
 		return 0, err
@@ -203,7 +196,7 @@ func (d OperatorImpl) Remove(ctx context.Context, query string, args ...any) (nu
 }
 
 func (d OperatorImpl) Update(ctx context.Context, query string, args ...any) (num int64, err error) {
-	res, err := d.conn.Exec(query, args...)
+	res, err := d.conn.Exec(d.Rebind(query), args...)
 	if err != nil {
 		logc.Errorf(ctx, "Update error: %s", err)
 		return 0, err
@@ -218,9 +211,18 @@ func (d OperatorImpl) Update(ctx context.Context, query string, args ...any) (nu
 }
 
 func (d OperatorImpl) Count(ctx context.Context, condition string, args ...any) (num int64, err error) {
-	query := "SELECT count(1) FROM " + d.TableName + condition
+	return d.count(ctx, "count(1)", condition, args...)
+}
+
+// CountDistinct counts the distinct non-NULL values of the quoted column.
+func (d OperatorImpl) CountDistinct(ctx context.Context, column, condition string, args ...any) (num int64, err error) {
+	return d.count(ctx, "count(DISTINCT "+column+")", condition, args...)
+}
 
-	err = d.conn.QueryRowCtx(ctx, &num, query, args...)
+func (d OperatorImpl) count(ctx context.Context, expr, condition string, args ...any) (num int64, err error) {
+	query := "SELECT " + expr + " FROM " + d.TableName + condition
+
+	err = d.conn.QueryRowCtx(ctx, &num, d.Rebind(query), args...)
 
 	switch {
 	case err == nil:
@@ -237,7 +239,7 @@ func (d OperatorImpl) Exist(ctx context.Context, condition string, args ...any)
 	query := "SELECT count(1) FROM " + d.TableName + condition
 
 	var num int64
-	err = d.conn.QueryRowCtx(ctx, &num, query, args...)
+	err = d.conn.QueryRowCtx(ctx, &num, d.Rebind(query), args...)
 
 	switch {
 	case err == nil:
@@ -274,3 +276,30 @@ func (d OperatorImpl) FindAll(ctx context.Context, model any, query string, args
 
 	return nil
 }
+
+// FindEach streams the rows into model, a pointer to struct, one by one and calls fn after each row.
+// go-zero only reads whole result sets, so the rows are read from its raw database/sql connection,
+// or from the transaction of the session, a session without one returns ErrSessionNotStreamable.
+func (d OperatorImpl) FindEach(ctx context.Context, model any, fn func() error, query string, args ...any) (err error) {
+	conn := d.raw
+	if conn == nil {
+		if d.session {
+			logc.Errorf(ctx, "FindEach error: %s", ErrSessionNotStreamable)
+			return ErrSessionNotStreamable
+		}
+		db, err := d.conn.RawDB()
+		if err != nil {
+			logc.Errorf(ctx, "FindEach error: %s", err)
+			return err
+		}
+		conn = db
+	}
+
+	rows, err := conn.QueryContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logc.Errorf(ctx, "FindEach error: %s", err)
+		return err
+	}
+
+	return stdsql.ScanEach(rows, model, d.DBTag, fn)
+}
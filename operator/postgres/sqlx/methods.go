@@ -0,0 +1,297 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"github.com/leisurelicht/norm/internal/logger"
+	"github.com/leisurelicht/norm/internal/operator"
+	pgOp "github.com/leisurelicht/norm/internal/operator/postgres"
+	"github.com/leisurelicht/norm/internal/operator/stdsql"
+)
+
+const (
+	dbTag       = "db"
+	placeholder = "$"
+	quote       = `"`
+	// uniqueViolation is the SQLSTATE of a unique constraint violation.
+	uniqueViolation = "23505"
+)
+
+type OperatorImpl struct {
+	conn stdsql.Conn
+	// returning is the column of the RETURNING clause of Insert, the primary key of the model unless set by Returning.
+	returning    string
+	hasReturning bool
+	operator.AddOptions
+}
+
+// NewOperator returns a PostgreSQL operator over a database/sql connection.
+// The driver (e.g. github.com/lib/pq or github.com/jackc/pgx/v5/stdlib) must be registered by the caller.
+func NewOperator(conn *sql.DB, opts ...operator.AddFunc) OperatorImpl {
+	addOptions := operator.DefaultAddOptions(dbTag)
+	addOptions.Placeholder = placeholder
+	addOptions.Quote = quote
+	for _, opt := range opts {
+		opt(&addOptions)
+	}
+	return OperatorImpl{
+		conn:       conn,
+		AddOptions: addOptions,
+	}
+}
+
+func WithTableName(tableName string) operator.AddFunc {
+	return operator.WithTableName(tableName)
+}
+
+// Returning sets the column returned by the INSERT ... RETURNING clause of Insert instead of the primary key of the model.
+// An empty column disables the clause, then Insert always returns 0 as id.
+func (d OperatorImpl) Returning(column string) OperatorImpl {
+	d.returning, d.hasReturning = column, true
+	return d
+}
+
+func (d OperatorImpl) SetTableName(tableName string) operator.Operator {
+	if d.TableName == "" {
+		d.TableName = tableName
+	}
+	return d
+}
+
+// SetPrimaryKey sets the column of the RETURNING clause of Insert to the primary key of the model,
+// unless it is set by Returning. A model without a primary key has no RETURNING clause.
+func (d OperatorImpl) SetPrimaryKey(column string) operator.Operator {
+	if !d.hasReturning {
+		d.returning = column
+	}
+	return d
+}
+
+// ReportsAffectedRows reports true, Update and Remove return the affected rows of the driver.
+func (d OperatorImpl) ReportsAffectedRows() bool {
+	return true
+}
+
+func (d OperatorImpl) GetTableName() string {
+	return d.TableName
+}
+
+func (d OperatorImpl) GetPlaceholder() string {
+	return d.Placeholder
+}
+
+func (d OperatorImpl) GetQuote() string {
+	return d.Quote
+}
+
+func (d OperatorImpl) GetDBTag() string {
+	return d.DBTag
+}
+
+// WithSession accepts a *sql.Tx (or *sql.Conn) to run the following operations in.
+func (d OperatorImpl) WithSession(session any) operator.Operator {
+	if conn, ok := session.(stdsql.Conn); ok {
+		d.conn = conn
+	}
+	return d
+}
+
+func (d OperatorImpl) OperatorSQL(operator, method string) string {
+	op, ok := pgOp.Operators[operator]
+	if !ok {
+		return ""
+	}
+	if method == "" {
+		return op
+	}
+	if methodSQL, ok := pgOp.Methods[method]; ok {
+		op = strings.ReplaceAll(op, "?", methodSQL)
+	}
+	return op
+}
+
+// LockSQL returns the row locking clause of the lock, e.g. " FOR UPDATE".
+func (d OperatorImpl) LockSQL(lock string) string {
+	return pgOp.Locks[lock]
+}
+
+// InsertSQL returns the insert template of the mode, e.g. "INSERT IGNORE INTO %s (%s) VALUES (%s)".
+func (d OperatorImpl) InsertSQL(mode string) string {
+	return pgOp.Inserts[mode]
+}
+
+// UpsertSQL returns the ON CONFLICT ... DO UPDATE clause.
+func (d OperatorImpl) UpsertSQL(conflictColumns, updateColumns []string) string {
+	return operator.OnConflictUpdate(conflictColumns, updateColumns)
+}
+
+func (d OperatorImpl) Insert(ctx context.Context, query string, args ...any) (id int64, err error) {
+	if d.returning == "" {
+		if _, err = d.conn.ExecContext(ctx, d.Rebind(query), args...); err != nil {
+			if isDuplicateKeyError(err) {
+				return 0, operator.ErrDuplicateKey
+			}
+			logger.Errorf("Insert error: %s", err)
+			return 0, err
+		}
+		return 0, nil
+	}
+
+	query += " RETURNING " + operator.QuoteIdent(d.Quote, d.returning)
+
+	err = d.conn.QueryRowContext(ctx, d.Rebind(query), args...).Scan(&id)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return 0, operator.ErrDuplicateKey
+		}
+		logger.Errorf("Insert error: %s", err)
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (d OperatorImpl) BulkInsert(ctx context.Context, query string, args []string, data []map[string]any) (num int64, err error) {
+	query, err = operator.BuildBulkInsertQuery(query, len(data))
+	if err != nil {
+		logger.Errorf("Build bulk insert query error: %s", err)
+		return 0, err
+	}
+
+	result, err := d.conn.ExecContext(ctx, d.Rebind(query), stdsql.BulkValues(args, data)...)
+	if err != nil {
+		if isDuplicateKeyError(err) {
+			return 0, operator.ErrDuplicateKey
+		}
+		logger.Errorf("Bulk insert error: %s", err)
+		return 0, err
+	}
+
+	num, err = result.RowsAffected()
+	if err != nil {
+		logger.Errorf("Bulk insert rows affected error: %s", err)
+		return 0, err
+	}
+
+	return num, nil
+}
+
+// isDuplicateKeyError checks the SQLSTATE exposed by both lib/pq and pgx errors.
+func isDuplicateKeyError(err error) bool {
+	var pgErr interface{ SQLState() string }
+	return errors.As(err, &pgErr) && pgErr.SQLState() == uniqueViolation
+}
+
+func (d OperatorImpl) exec(ctx context.Context, name, query string, args ...any) (num int64, err error) {
+	res, err := d.conn.ExecContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("%s error: %s", name, err)
+		return 0, err
+	}
+
+	num, err = res.RowsAffected()
+	if err != nil {
+		logger.Errorf("%s rows affected error: %s", name, err)
+		return 0, err
+	}
+	return num, nil
+}
+
+func (d OperatorImpl) Remove(ctx context.Context, query string, args ...any) (num int64, err error) {
+	return d.exec(ctx, "Remove", query, args...)
+}
+
+func (d OperatorImpl) Update(ctx context.Context, query string, args ...any) (num int64, err error) {
+	return d.exec(ctx, "Update", query, args...)
+}
+
+func (d OperatorImpl) Count(ctx context.Context, condition string, args ...any) (num int64, err error) {
+	return d.count(ctx, "count(1)", condition, args...)
+}
+
+// CountDistinct counts the distinct non-NULL values of the quoted column.
+func (d OperatorImpl) CountDistinct(ctx context.Context, column, condition string, args ...any) (num int64, err error) {
+	return d.count(ctx, "count(DISTINCT "+column+")", condition, args...)
+}
+
+func (d OperatorImpl) count(ctx context.Context, expr, condition string, args ...any) (num int64, err error) {
+	query := "SELECT " + expr + " FROM " + d.TableName + condition
+
+	err = d.conn.QueryRowContext(ctx, d.Rebind(query), args...).Scan(&num)
+
+	switch {
+	case err == nil:
+		return num, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return 0, nil
+	default:
+		logger.Errorf("Count error: %s", err)
+		return 0, err
+	}
+}
+
+func (d OperatorImpl) Exist(ctx context.Context, condition string, args ...any) (exist bool, err error) {
+	query := "SELECT count(1) FROM " + d.TableName + condition
+
+	var num int64
+	err = d.conn.QueryRowContext(ctx, d.Rebind(query), args...).Scan(&num)
+
+	switch {
+	case err == nil:
+		return num > 0, nil
+	case errors.Is(err, sql.ErrNoRows):
+		return false, nil
+	default:
+		logger.Errorf("Exist error: %s", err)
+		return false, err
+	}
+}
+
+func (d OperatorImpl) FindOne(ctx context.Context, model any, query string, args ...any) (err error) {
+	rows, err := d.conn.QueryContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("FindOne error: %s", err)
+		return err
+	}
+
+	err = stdsql.ScanRow(rows, model, d.DBTag)
+
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, operator.ErrNotFound):
+		return operator.ErrNotFound
+	default:
+		logger.Errorf("FindOne error: %s", err)
+		return err
+	}
+}
+
+func (d OperatorImpl) FindAll(ctx context.Context, model any, query string, args ...any) (err error) {
+	rows, err := d.conn.QueryContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("FindAll error: %s", err)
+		return err
+	}
+
+	if err = stdsql.ScanRows(rows, model, d.DBTag); err != nil {
+		logger.Errorf("FindAll error: %s", err)
+		return err
+	}
+
+	return nil
+}
+
+// FindEach streams the rows into model, a pointer to struct, one by one and calls fn after each row.
+func (d OperatorImpl) FindEach(ctx context.Context, model any, fn func() error, query string, args ...any) (err error) {
+	rows, err := d.conn.QueryContext(ctx, d.Rebind(query), args...)
+	if err != nil {
+		logger.Errorf("FindEach error: %s", err)
+		return err
+	}
+
+	return stdsql.ScanEach(rows, model, d.DBTag, fn)
+}
@@ -0,0 +1,175 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+type pgError struct {
+	code string
+}
+
+func (e *pgError) Error() string    { return "pq: " + e.code }
+func (e *pgError) SQLState() string { return e.code }
+
+func TestNewOperator(t *testing.T) {
+	op := NewOperator(nil)
+	if op.GetPlaceholder() != "$" {
+		t.Errorf("got placeholder %q, want %q", op.GetPlaceholder(), "$")
+	}
+	if op.GetQuote() != `"` {
+		t.Errorf("got quote %q, want %q", op.GetQuote(), `"`)
+	}
+	if op.GetDBTag() != "db" {
+		t.Errorf("got db tag %q, want %q", op.GetDBTag(), "db")
+	}
+
+	named := NewOperator(nil, WithTableName("users"))
+	if got := named.SetTableName(`"other"`).GetTableName(); got != "users" {
+		t.Errorf("got table name %q, want %q", got, "users")
+	}
+}
+
+// fakeDB logs the statements run through its connections, each query returns the id 7.
+type fakeDB struct {
+	log []string
+}
+
+type fakeConn struct{ db *fakeDB }
+
+type fakeStmt struct{ db *fakeDB }
+
+type fakeRows struct{ done bool }
+
+func (db *fakeDB) Connect(context.Context) (driver.Conn, error) { return fakeConn{db}, nil }
+func (db *fakeDB) Driver() driver.Driver                        { return nil }
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	c.db.log = append(c.db.log, query)
+	return fakeStmt(c), nil
+}
+func (c fakeConn) Close() error              { return nil }
+func (c fakeConn) Begin() (driver.Tx, error) { return nil, errors.New("not supported") }
+
+func (s fakeStmt) Close() error                               { return nil }
+func (s fakeStmt) NumInput() int                              { return -1 }
+func (s fakeStmt) Exec([]driver.Value) (driver.Result, error) { return driver.RowsAffected(1), nil }
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error)  { return &fakeRows{}, nil }
+func (r *fakeRows) Columns() []string                         { return []string{"id"} }
+func (r *fakeRows) Close() error                              { return nil }
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.done {
+		return io.EOF
+	}
+	dest[0], r.done = int64(7), true
+	return nil
+}
+
+func TestInsertReturning(t *testing.T) {
+	const query = `INSERT INTO "users" ("name") VALUES (?)`
+	tests := []struct {
+		name string
+		op   func(db *sql.DB) OperatorImpl
+		sql  string
+		id   int64
+	}{
+		{"no primary key", func(db *sql.DB) OperatorImpl {
+			return NewOperator(db)
+		}, `INSERT INTO "users" ("name") VALUES ($1)`, 0},
+		{"primary key", func(db *sql.DB) OperatorImpl {
+			return NewOperator(db).SetPrimaryKey("uid").(OperatorImpl)
+		}, `INSERT INTO "users" ("name") VALUES ($1) RETURNING "uid"`, 7},
+		{"returning", func(db *sql.DB) OperatorImpl {
+			return NewOperator(db).Returning("seq").SetPrimaryKey("uid").(OperatorImpl)
+		}, `INSERT INTO "users" ("name") VALUES ($1) RETURNING "seq"`, 7},
+		{"returning disabled", func(db *sql.DB) OperatorImpl {
+			return NewOperator(db).Returning("").SetPrimaryKey("uid").(OperatorImpl)
+		}, `INSERT INTO "users" ("name") VALUES ($1)`, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fake := &fakeDB{}
+			db := sql.OpenDB(fake)
+			defer func() { _ = db.Close() }()
+
+			id, err := tt.op(db).Insert(context.Background(), query, "a")
+			if err != nil {
+				t.Fatalf("Insert error: %v", err)
+			}
+			if id != tt.id {
+				t.Errorf("got id %d, want %d", id, tt.id)
+			}
+			if len(fake.log) != 1 || fake.log[0] != tt.sql {
+				t.Errorf("got %q, want %q", fake.log, tt.sql)
+			}
+		})
+	}
+}
+
+func TestOperatorSQL(t *testing.T) {
+	op := NewOperator(nil)
+	tests := []struct {
+		operator string
+		method   string
+		want     string
+	}{
+		{"exact", "", `"%s" = ?`},
+		{"iexact", "", `"%s" ILIKE ?`},
+		{"icontains", "", `"%s"%s ILIKE ?`},
+		{"exact", "toDate", `"%s" = ?::date`},
+		{"unknown", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.operator+tt.method, func(t *testing.T) {
+			if got := op.OperatorSQL(tt.operator, tt.method); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLockSQL(t *testing.T) {
+	op := NewOperator(nil)
+	tests := []struct {
+		lock string
+		want string
+	}{
+		{"for_update", " FOR UPDATE"},
+		{"for_share", " FOR SHARE"},
+		{"skip_locked", " SKIP LOCKED"},
+		{"nowait", " NOWAIT"},
+		{"unknown", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lock, func(t *testing.T) {
+			if got := op.LockSQL(tt.lock); got != tt.want {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsDuplicateKeyError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"unique violation", &pgError{code: "23505"}, true},
+		{"wrapped unique violation", fmt.Errorf("insert: %w", &pgError{code: "23505"}), true},
+		{"foreign key violation", &pgError{code: "23503"}, false},
+		{"plain error", errors.New("23505"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isDuplicateKeyError(tt.err); got != tt.want {
+				t.Errorf("got %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,11 +1,18 @@
 package norm
 
 import (
+	"bytes"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 	"strings"
+	"time"
+
+	"github.com/leisurelicht/norm/internal/operator"
 )
 
 // shiftName shift name like DevicePolicyMap to device_policy_map
@@ -73,6 +80,15 @@ func rawFieldNames(in any, tag string, pg bool) []string {
 	return out
 }
 
+// quoteFieldNames wraps each field name with the given identifier quote
+func quoteFieldNames(fieldNames []string, quote string) []string {
+	out := make([]string, len(fieldNames))
+	for i, name := range fieldNames {
+		out[i] = operator.QuoteIdent(quote, name)
+	}
+	return out
+}
+
 // strSlice2Map convert string slice to map
 func strSlice2Map(s []string) (res map[string]struct{}) {
 	res = make(map[string]struct{})
@@ -94,70 +110,64 @@ func modelStruct2Map(obj any, tag string) map[string]any {
 	data := make(map[string]any, t.NumField())
 
 	for i := 0; i < t.NumField(); i++ {
-		tagVal := t.Field(i).Tag.Get(tag)
+		tagVal := strings.TrimSpace(strings.Split(t.Field(i).Tag.Get(tag), ",")[0])
 		// skip fields without tag or explicitly ignored
 		if tagVal == "" || tagVal == "-" {
 			continue
 		}
 
 		field := v.Field(i)
-		value := field.Interface()
+		data[tagVal] = normalizeNullValue(field.Interface())
+	}
+	return data
+}
 
-		// normalize common sql.Null* types without using reflect.TypeOf on each iteration
-		switch nv := value.(type) {
-		case sql.NullByte:
-			if nv.Valid {
-				value = nv.Byte
-			} else {
-				value = nil
-			}
-		case sql.NullBool:
-			if nv.Valid {
-				value = nv.Bool
-			} else {
-				value = nil
-			}
-		case sql.NullFloat64:
-			if nv.Valid {
-				value = nv.Float64
-			} else {
-				value = nil
-			}
-		case sql.NullInt16:
-			if nv.Valid {
-				value = nv.Int16
-			} else {
-				value = nil
-			}
-		case sql.NullInt32:
-			if nv.Valid {
-				value = nv.Int32
-			} else {
-				value = nil
-			}
-		case sql.NullInt64:
-			if nv.Valid {
-				value = nv.Int64
-			} else {
-				value = nil
-			}
-		case sql.NullString:
-			if nv.Valid {
-				value = nv.String
-			} else {
-				value = nil
-			}
-		case sql.NullTime:
-			if nv.Valid {
-				value = nv.Time
-			} else {
-				value = nil
-			}
+// normalizeNullValue returns the value of the common sql.Null* types, or nil if it is not valid.
+func normalizeNullValue(value any) any {
+	// normalize common sql.Null* types without using reflect.TypeOf on each iteration
+	switch nv := value.(type) {
+	case sql.NullByte:
+		if nv.Valid {
+			return nv.Byte
 		}
-
-		data[tagVal] = value
+		return nil
+	case sql.NullBool:
+		if nv.Valid {
+			return nv.Bool
+		}
+		return nil
+	case sql.NullFloat64:
+		if nv.Valid {
+			return nv.Float64
+		}
+		return nil
+	case sql.NullInt16:
+		if nv.Valid {
+			return nv.Int16
+		}
+		return nil
+	case sql.NullInt32:
+		if nv.Valid {
+			return nv.Int32
+		}
+		return nil
+	case sql.NullInt64:
+		if nv.Valid {
+			return nv.Int64
+		}
+		return nil
+	case sql.NullString:
+		if nv.Valid {
+			return nv.String
+		}
+		return nil
+	case sql.NullTime:
+		if nv.Valid {
+			return nv.Time
+		}
+		return nil
 	}
-	return data
+	return value
 }
 
 // modelStructSlice2MapSlice convert struct slice to map slice, tag is the tag name of struct
@@ -248,7 +258,7 @@ func parseSelectColumns(selectSQL string) []string {
 			col = strings.TrimSpace(col[idx+1:])
 		}
 
-		col = strings.Trim(col, "`")
+		col = strings.Trim(col, "`\"")
 		if col != "" {
 			cols = append(cols, col)
 		}
@@ -586,3 +596,291 @@ func filterBySelectColumns(result map[string]any, selectSQL string) map[string]a
 	}
 	return filtered
 }
+
+// rawFieldTypes returns the field types of the model by their column names.
+func rawFieldTypes(in any, tag string) map[string]reflect.Type {
+	typ := reflect.TypeOf(in)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	types := make(map[string]reflect.Type, typ.NumField())
+	for i := range typ.NumField() {
+		fi := typ.Field(i)
+		name := strings.TrimSpace(strings.Split(fi.Tag.Get(tag), ",")[0])
+		switch name {
+		case "-":
+			continue
+		case "":
+			name = fi.Name
+		}
+		types[name] = fi.Type
+	}
+	return types
+}
+
+// taggedColumns returns the columns of the model having the tag option,
+// e.g. "deleted_at" for `db:"deleted_at,softDelete"` with the option softDelete.
+func taggedColumns(in any, tag, option string) []string {
+	typ := reflect.TypeOf(in)
+	if typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	var columns []string
+	for i := range typ.NumField() {
+		fi := typ.Field(i)
+		name, options, _ := strings.Cut(fi.Tag.Get(tag), ",")
+		if name = strings.TrimSpace(name); name == "-" {
+			continue
+		}
+		if name == "" {
+			name = fi.Name
+		}
+		for opt := range strings.SplitSeq(options, ",") {
+			if strings.TrimSpace(opt) == option {
+				columns = append(columns, name)
+				break
+			}
+		}
+	}
+	return columns
+}
+
+// taggedColumn returns the first column of the model having the tag option, or "" if there is none.
+func taggedColumn(in any, tag, option string) string {
+	if columns := taggedColumns(in, tag, option); len(columns) > 0 {
+		return columns[0]
+	}
+	return ""
+}
+
+// fieldByColumn returns the field of the struct value v for the column, it is invalid if there is none.
+func fieldByColumn(v reflect.Value, tag, column string) reflect.Value {
+	typ := v.Type()
+	for i := range typ.NumField() {
+		fi := typ.Field(i)
+		name := strings.TrimSpace(strings.Split(fi.Tag.Get(tag), ",")[0])
+		if name == "" {
+			name = fi.Name
+		}
+		if name == column && fi.IsExported() {
+			return v.Field(i)
+		}
+	}
+	return reflect.Value{}
+}
+
+// setField sets the field to the value, converting a number to the number type of the field,
+// a pointer field gets a pointer to it and a sql.Scanner field like sql.NullTime scans it.
+// It reports whether the field is set, it is not if the value does not fit the field.
+func setField(field reflect.Value, value any) bool {
+	if !field.CanSet() || value == nil {
+		return false
+	}
+
+	rv := reflect.ValueOf(value)
+	typ := field.Type()
+	switch {
+	case rv.Type().AssignableTo(typ):
+		field.Set(rv)
+	case typ.Kind() == reflect.Pointer && rv.Type().AssignableTo(typ.Elem()):
+		ptr := reflect.New(typ.Elem())
+		ptr.Elem().Set(rv)
+		field.Set(ptr)
+	case isNumberKind(rv.Kind()) && isNumberKind(typ.Kind()):
+		field.Set(rv.Convert(typ))
+	default:
+		scanner, ok := field.Addr().Interface().(sql.Scanner)
+		return ok && scanner.Scan(value) == nil
+	}
+	return true
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return kind >= reflect.Int && kind <= reflect.Float64
+}
+
+// isZeroValue reports whether the value is nil or the zero value of its type.
+func isZeroValue(value any) bool {
+	return value == nil || reflect.ValueOf(value).IsZero()
+}
+
+// sameValue reports whether the values are equal, the times are compared by Equal.
+func sameValue(a, b any) bool {
+	if ta, ok := a.(time.Time); ok {
+		tb, ok := b.(time.Time)
+		return ok && ta.Equal(tb)
+	}
+	return reflect.DeepEqual(a, b)
+}
+
+// isTimeType reports whether the field type holds a time, like time.Time, *time.Time or sql.NullTime.
+func isTimeType(typ reflect.Type) bool {
+	if typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	return typ == reflect.TypeFor[time.Time]() || typ == reflect.TypeFor[sql.NullTime]()
+}
+
+// unqualifiedName returns the column name without the table, e.g. "name" for "source.name".
+func unqualifiedName(column string) string {
+	if pos := strings.LastIndex(column, "."); pos != -1 {
+		return column[pos+1:]
+	}
+	return column
+}
+
+// resultColumn is a column of a query result which does not fit the model, like an aggregation.
+type resultColumn struct {
+	name string
+	typ  reflect.Type
+}
+
+// resultStructType returns a struct type which has a field tagged by the name of each column.
+func resultStructType(columns []resultColumn, tag string) reflect.Type {
+	fields := make([]reflect.StructField, len(columns))
+	for i, column := range columns {
+		fields[i] = reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: column.typ,
+			Tag:  reflect.StructTag(fmt.Sprintf(`%s:"%s"`, tag, column.name)),
+		}
+	}
+	return reflect.StructOf(fields)
+}
+
+// resultStruct2Map converts a struct of resultStructType to a map,
+// nil pointers and invalid sql.Null* values become nil.
+func resultStruct2Map(obj any, columns []resultColumn) map[string]any {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+
+	data := make(map[string]any, len(columns))
+	for i, column := range columns {
+		data[column.name] = resultValue(v.Field(i))
+	}
+	return data
+}
+
+// resultValue returns the value of a field of the struct created by resultStructType.
+func resultValue(field reflect.Value) any {
+	if field.Kind() == reflect.Ptr || field.Kind() == reflect.Interface {
+		if field.IsNil() {
+			return nil
+		}
+		field = field.Elem()
+	}
+
+	value := normalizeNullValue(field.Interface())
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return value
+}
+
+// resultStructSlice2MapSlice converts a pointer to a slice of the struct of resultStructType to maps.
+func resultStructSlice2MapSlice(obj any, columns []resultColumn) []map[string]any {
+	v := reflect.Indirect(reflect.ValueOf(obj))
+
+	data := make([]map[string]any, 0, v.Len())
+	for i := range v.Len() {
+		data = append(data, resultStruct2Map(v.Index(i).Interface(), columns))
+	}
+	return data
+}
+
+// reverseSlice reverses a slice or a pointer to a slice in place.
+func reverseSlice(slice any) {
+	v := reflect.Indirect(reflect.ValueOf(slice))
+	swap := reflect.Swapper(v.Interface())
+	for i, j := 0, v.Len()-1; i < j; i, j = i+1, j-1 {
+		swap(i, j)
+	}
+}
+
+// keysetCursor is the content of the cursor of After and Before, the values of the OrderBy columns of a row.
+type keysetCursor struct {
+	OrderBy []string          `json:"o"`
+	Values  []json.RawMessage `json:"v"`
+}
+
+// encodeCursor encodes the values of the orderBy columns into an opaque cursor.
+func encodeCursor(orderBy []string, values []any) (string, error) {
+	c := keysetCursor{OrderBy: orderBy, Values: make([]json.RawMessage, len(values))}
+	for i, value := range values {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		c.Values[i] = raw
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// decodeCursor decodes a cursor of encodeCursor, the values are decoded into the types of the columns,
+// types is indexed like orderBy and an unknown type decodes a JSON number into int64 or float64.
+func decodeCursor(cursor string, orderBy []string, types []reflect.Type) ([]any, error) {
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, fmt.Errorf(CursorInvalidError, err)
+	}
+
+	var c keysetCursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return nil, fmt.Errorf(CursorInvalidError, err)
+	}
+	if !slices.Equal(c.OrderBy, orderBy) || len(c.Values) != len(orderBy) {
+		return nil, fmt.Errorf(CursorMismatchError, strings.Join(c.OrderBy, ", "), strings.Join(orderBy, ", "))
+	}
+
+	values := make([]any, len(c.Values))
+	for i, raw := range c.Values {
+		if values[i], err = cursorValue(raw, types[i]); err != nil {
+			return nil, fmt.Errorf(CursorInvalidError, err)
+		}
+	}
+	return values, nil
+}
+
+// cursorValue decodes a value of the cursor into typ, interfaces, the sql.Null* and other struct types
+// except time.Time are decoded like an unknown type.
+func cursorValue(raw json.RawMessage, typ reflect.Type) (any, error) {
+	for typ != nil && typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ != nil && typ.Kind() != reflect.Interface && (typ.Kind() != reflect.Struct || typ == reflect.TypeFor[time.Time]()) {
+		v := reflect.New(typ)
+		if err := json.Unmarshal(raw, v.Interface()); err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}
+
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return nil, err
+	}
+	if n, ok := value.(json.Number); ok {
+		if i, err := n.Int64(); err == nil {
+			return i, nil
+		}
+		return n.Float64()
+	}
+	return value, nil
+}
+
+// andWhere adds a condition to the WHERE clause of GetQuerySet by AND, the clause is wrapped by parentheses
+// since it can be a single Where joined by OR.
+func andWhere(filterSQL, condition string) string {
+	if filterSQL == "" {
+		return " WHERE " + condition
+	}
+	return " WHERE (" + strings.TrimPrefix(filterSQL, " WHERE ") + ") AND " + condition
+}
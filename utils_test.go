@@ -153,6 +153,10 @@ func Test_modelStruct2Map(t *testing.T) {
 		want map[string]any
 	}{
 		{"test tempty", args{struct{}{}, "db"}, map[string]any{}},
+		{"test tag option", args{struct {
+			Id        int64 `db:"id"`
+			IsDeleted bool  `db:"is_deleted,softDelete"`
+		}{Id: 1, IsDeleted: true}, "db"}, map[string]any{"id": int64(1), "is_deleted": true}},
 		{"test more", args{struct {
 			Id              int64           `db:"id"`
 			TestInt         int             `db:"test_int"`